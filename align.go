@@ -0,0 +1,123 @@
+package uvcasso
+
+import (
+	"fmt"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// Align places a segment along the cross axis of its layout.
+type Align int
+
+const (
+	AlignStretch Align = iota
+	AlignStart
+	AlignCenter
+	AlignEnd
+)
+
+// Aligned sizes the segment along the cross axis by Size and places it
+// by Align, while along the main axis the segment is sized by the wrapped
+// Constraint, e.g. a button 3 cells tall centered in a horizontal row:
+//
+//	Horizontal(Fill(1), Aligned{Constraint: Len(10), Size: Len(3), Align: AlignCenter})
+//
+// The segment spans the whole cross axis when Size is nil or Align is
+// [AlignStretch], the zero value, which ignores Size. Spacers always span
+// the whole cross axis. [Auto] and [Measured] sizes are called with the
+// size of the segment along the main axis.
+//
+// Alignment applies to [Layout] and [System], nodes of a [Node] tree
+// always span the cross axis of their parent.
+type Aligned struct {
+	Constraint Constraint
+	Size       Constraint
+	Align      Align
+}
+
+func (a Aligned) String() string {
+	return fmt.Sprintf("Aligned(%v, %v, %d)", a.Constraint, a.Size, a.Align)
+}
+func (Aligned) isConstraint() {}
+
+func (a Aligned) unwrap() Constraint { return a.Constraint }
+
+func (a Aligned) wrap(c Constraint) Constraint {
+	a.Constraint = c
+	return a
+}
+
+// findAligned returns the outermost [Aligned] wrapper of the constraint.
+func findAligned(c Constraint) (Aligned, bool) {
+	for {
+		switch w := c.(type) {
+		case Aligned:
+			return w, true
+		case _wrapper:
+			c = w.unwrap()
+		default:
+			return Aligned{}, false
+		}
+	}
+}
+
+// alignSegments shrinks the segments of the layout along the cross axis
+// as their [Aligned] constraints say.
+func (l Layout) alignSegments(segments []uv.Rectangle) {
+	crossDirection := DirectionHorizontal
+	if l.Direction == DirectionHorizontal {
+		crossDirection = DirectionVertical
+	}
+
+	for i := 0; i < min(len(l.Constraints), len(segments)); i++ {
+		aligned, ok := findAligned(l.Constraints[i])
+		if !ok || aligned.Size == nil || aligned.Align == AlignStretch {
+			continue
+		}
+
+		segment := segments[i]
+
+		main, cross := segment.Dy(), segment.Dx()
+		if l.Direction == DirectionHorizontal {
+			main, cross = segment.Dx(), segment.Dy()
+		}
+
+		size := crossSize(aligned.Size, crossDirection, cross, main)
+
+		var offset int
+
+		switch aligned.Align {
+		case AlignCenter:
+			offset = (cross - size) / 2
+		case AlignEnd:
+			offset = cross - size
+		}
+
+		switch l.Direction {
+		case DirectionHorizontal:
+			segment.Min.Y += offset
+			segment.Max.Y = segment.Min.Y + size
+		case DirectionVertical:
+			segment.Min.X += offset
+			segment.Max.X = segment.Min.X + size
+		}
+
+		segments[i] = segment
+	}
+}
+
+// crossSize returns the size of a constraint along the cross axis, e.g. the
+// Size of an [Aligned] segment, clamped to the cross size. Main is the size
+// along the main axis. [Fill] and [Min] take the whole cross size.
+func crossSize(c Constraint, direction Direction, cross, main int) int {
+	var size int
+
+	switch baseConstraint(c).(type) {
+	case Fill, Min:
+		size = cross
+	default:
+		size = flowBasis(c, direction, cross, main)
+	}
+
+	return max(0, min(size, cross))
+}
@@ -0,0 +1,107 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAligned(t *testing.T) {
+	t.Run("button next to list", func(t *testing.T) {
+		layout := Horizontal(Fill(1), Aligned{Constraint: Len(10), Size: Len(3), Align: AlignCenter})
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 10, 9),
+			uv.Rect(10, 3, 10, 3),
+		}, layout.Split(uv.Rect(0, 0, 20, 9)))
+	})
+
+	for _, tc := range []struct {
+		name     string
+		align    Align
+		size     Constraint
+		expected uv.Rectangle
+	}{
+		{name: "start", align: AlignStart, size: Len(4), expected: uv.Rect(0, 0, 4, 2)},
+		{name: "center", align: AlignCenter, size: Len(4), expected: uv.Rect(3, 0, 4, 2)},
+		{name: "end", align: AlignEnd, size: Len(4), expected: uv.Rect(6, 0, 4, 2)},
+		{name: "stretch", align: AlignStretch, size: Len(4), expected: uv.Rect(0, 0, 10, 2)},
+		{name: "no size", align: AlignEnd, expected: uv.Rect(0, 0, 10, 2)},
+		{name: "overflow", align: AlignCenter, size: Len(20), expected: uv.Rect(0, 0, 10, 2)},
+		{name: "percentage", align: AlignEnd, size: Percentage(50), expected: uv.Rect(5, 0, 5, 2)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			layout := Vertical(Aligned{Constraint: Len(2), Size: tc.size, Align: tc.align}, Fill(1))
+
+			segments, spacers := layout.SplitWithSpacers(uv.Rect(0, 0, 10, 5))
+
+			require.Equal(t, tc.expected, segments[0])
+			require.Equal(t, uv.Rect(0, 2, 10, 3), segments[1])
+
+			for _, spacer := range spacers {
+				require.Equal(t, 10, spacer.Dx())
+			}
+		})
+	}
+
+	t.Run("wrapped", func(t *testing.T) {
+		layout := Horizontal(
+			Grouped{Aligned{Constraint: Len(4), Size: Len(1), Align: AlignEnd}, "g", 0},
+			Grouped{Fill(1), "g", 0},
+		)
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 2, 4, 1),
+			uv.Rect(4, 0, 4, 3),
+		}, layout.Split(uv.Rect(0, 0, 8, 3)))
+	})
+
+	t.Run("auto sizes", func(t *testing.T) {
+		area := uv.Rect(0, 0, 10, 9)
+
+		// Both layouts share a key of the global cache.
+		for _, size := range []int{2, 5} {
+			layout := Horizontal(Aligned{
+				Constraint: Len(4),
+				Size:       Auto(func(int) int { return size }),
+				Align:      AlignStart,
+			}).WithFlex(FlexStart)
+
+			require.Equal(t, uv.Rect(0, 0, 4, size), layout.Split(area)[0])
+		}
+	})
+
+	t.Run("auto size of segment", func(t *testing.T) {
+		var got int
+
+		layout := Vertical(Aligned{
+			Constraint: Len(2),
+			Size: Auto(func(height int) int {
+				got = height
+				return 3
+			}),
+			Align: AlignEnd,
+		}).WithFlex(FlexStart)
+
+		require.Equal(t, uv.Rect(7, 0, 3, 2), layout.Split(uv.Rect(0, 0, 10, 5))[0])
+		require.Equal(t, 2, got)
+	})
+
+	t.Run("measure", func(t *testing.T) {
+		layout := Horizontal(Fill(1), Aligned{Constraint: Len(10), Size: Len(3), Align: AlignCenter})
+
+		require.Equal(t, Measurement{Min: 3, Preferred: 3}, layout.Measure(DirectionVertical, 20))
+	})
+
+	t.Run("system", func(t *testing.T) {
+		result := NewSystem().
+			WithLayout("row", Horizontal(Len(4), Aligned{Constraint: Len(4), Size: Len(1), Align: AlignCenter}), uv.Rect(0, 0, 8, 3)).
+			Split()
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 4, 3),
+			uv.Rect(4, 1, 4, 1),
+		}, result["row"])
+	})
+}
@@ -0,0 +1,168 @@
+package uvcasso
+
+import (
+	"container/list"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// DefaultCacheSize is the number of split results kept by the global cache.
+const DefaultCacheSize = 500
+
+var _globalCache = NewCache(DefaultCacheSize)
+
+// SetCacheSize changes the size of the global cache used by layouts
+// without their own [Cache]. Size of zero or less disables it.
+func SetCacheSize(size int) {
+	_globalCache.Resize(size)
+}
+
+// ClearCache drops all entries of the global cache.
+func ClearCache() {
+	_globalCache.Clear()
+}
+
+// Cache is a bounded LRU cache of split results.
+//
+// It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   list.List
+}
+
+type _CacheEntry struct {
+	key               string
+	segments, spacers Splitted
+	hidden            []int
+}
+
+// NewCache creates a new cache holding at most size entries.
+// Size of zero or less creates a disabled cache.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    max(0, size),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Resize changes the maximum number of entries, evicting the least
+// recently used ones if needed. Size of zero or less disables the cache.
+func (c *Cache) Resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.size = max(0, size)
+	c.evict()
+}
+
+// Clear drops all entries.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.order.Init()
+}
+
+// Len returns the number of entries.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache) get(key string) (segments, spacers Splitted, hidden []int, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	entry := element.Value.(*_CacheEntry)
+
+	return slices.Clone(entry.segments), slices.Clone(entry.spacers), slices.Clone(entry.hidden), true
+}
+
+func (c *Cache) put(key string, segments, spacers Splitted, hidden []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size == 0 {
+		return
+	}
+
+	entry := &_CacheEntry{
+		key:      key,
+		segments: slices.Clone(segments),
+		spacers:  slices.Clone(spacers),
+		hidden:   slices.Clone(hidden),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	c.evict()
+}
+
+func (c *Cache) evict() {
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*_CacheEntry).key)
+	}
+}
+
+// cacheKey returns a canonical encoding of everything that affects
+// the result of splitting the given area.
+func (l Layout) cacheKey(area uv.Rectangle) string {
+	var b strings.Builder
+
+	writeInt := func(values ...int) {
+		for _, v := range values {
+			b.WriteString(strconv.Itoa(v))
+			b.WriteByte(',')
+		}
+
+		b.WriteByte('|')
+	}
+
+	writeInt(int(l.Direction), int(l.Flex), spacingSize(l.Spacing))
+	writeInt(l.Padding.Top, l.Padding.Right, l.Padding.Bottom, l.Padding.Left)
+	writeInt(area.Min.X, area.Min.Y, area.Max.X, area.Max.Y)
+
+	writeConstraints := func(constraints []Constraint) {
+		for _, c := range constraints {
+			if c == nil {
+				b.WriteString("nil")
+			} else {
+				b.WriteString(c.String())
+			}
+
+			b.WriteByte(';')
+		}
+
+		b.WriteByte('|')
+	}
+
+	writeConstraints(l.Constraints)
+	writeConstraints(l.Gaps)
+
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package uvcasso
+
+import (
+	"sync"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("stores split results", func(t *testing.T) {
+		cache := NewCache(10)
+		layout := Horizontal(Len(3), Fill(1)).WithCache(cache)
+
+		area := uv.Rect(0, 0, 10, 1)
+
+		want := layout.Split(area)
+
+		require.Equal(t, 1, cache.Len())
+		require.Equal(t, want, layout.Split(area))
+		require.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("returns copies", func(t *testing.T) {
+		cache := NewCache(10)
+		layout := Horizontal(Len(3), Fill(1)).WithCache(cache)
+
+		area := uv.Rect(0, 0, 10, 1)
+
+		segments := layout.Split(area)
+		segments[0] = uv.Rectangle{}
+
+		require.Equal(t, uv.Rect(0, 0, 3, 1), layout.Split(area)[0])
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		cache := NewCache(2)
+		layout := Horizontal(Len(3), Fill(1)).WithCache(cache)
+
+		first := uv.Rect(0, 0, 10, 1)
+		second := uv.Rect(0, 0, 20, 1)
+		third := uv.Rect(0, 0, 30, 1)
+
+		layout.Split(first)
+		layout.Split(second)
+		layout.Split(first)
+		layout.Split(third)
+
+		require.Equal(t, 2, cache.Len())
+
+		_, _, _, ok := cache.get(layout.cacheKey(first))
+		require.True(t, ok)
+
+		_, _, _, ok = cache.get(layout.cacheKey(second))
+		require.False(t, ok)
+	})
+
+	t.Run("resize and clear", func(t *testing.T) {
+		cache := NewCache(10)
+		layout := Horizontal(Len(3), Fill(1)).WithCache(cache)
+
+		for width := range 5 {
+			layout.Split(uv.Rect(0, 0, width, 1))
+		}
+
+		require.Equal(t, 5, cache.Len())
+
+		cache.Resize(3)
+		require.Equal(t, 3, cache.Len())
+
+		cache.Clear()
+		require.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		layout := Horizontal(Len(3), Fill(1)).WithoutCache()
+
+		layout.Split(uv.Rect(0, 0, 10, 1))
+
+		require.Equal(t, 0, layout.Cache.Len())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		cache := NewCache(4)
+		layout := Horizontal(Len(3), Fill(1), Percentage(20)).WithCache(cache)
+
+		var wg sync.WaitGroup
+
+		for i := range 8 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for width := range 16 {
+					area := uv.Rect(0, 0, width+i, 1)
+
+					assert.Equal(t, layout.WithoutCache().Split(area), layout.Split(area))
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		require.LessOrEqual(t, cache.Len(), 4)
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	area := uv.Rect(0, 0, 10, 1)
+	base := Horizontal(Len(3), Fill(1))
+
+	keys := []string{
+		base.cacheKey(area),
+		base.cacheKey(uv.Rect(1, 0, 10, 1)),
+		base.WithDirection(DirectionVertical).cacheKey(area),
+		base.WithFlex(FlexCenter).cacheKey(area),
+		base.WithSpacing(SpacingSpace(1)).cacheKey(area),
+		base.WithSpacing(SpacingOverlap(1)).cacheKey(area),
+		base.WithPadding(NewPadding(1)).cacheKey(area),
+		base.WithConstraints(Min(1)).cacheKey(area),
+		Horizontal(Len(3), Fill(2)).cacheKey(area),
+	}
+
+	seen := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		require.NotContains(t, seen, key)
+
+		seen[key] = struct{}{}
+	}
+
+	require.Equal(t, base.cacheKey(area), base.WithCache(NewCache(1)).cacheKey(area))
+}
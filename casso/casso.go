@@ -3,11 +3,18 @@ package casso
 import (
 	"maps"
 	"slices"
-	"sync/atomic"
+	"strconv"
 )
 
+// Strength is the weight of a constraint. Constraints with higher strength
+// take precedence over weaker ones when not all of them can be satisfied.
 type Strength float64
 
+// Predefined strengths. Any value in between can be used as well,
+// e.g. Strong * 10 is stronger than Strong but still weaker than Required.
+//
+// Required constraints must always hold, the solver reports
+// [ErrUnsatisfiableConstraint] for ones which can not.
 const (
 	Required Strength = 1_001_001_000
 	Strong   Strength = 1_000_000
@@ -15,29 +22,57 @@ const (
 	Weak     Strength = 1
 )
 
+// String returns the name of a predefined strength,
+// or the numeric value for any other.
+func (s Strength) String() string {
+	switch s {
+	case Required:
+		return "required"
+	case Strong:
+		return "strong"
+	case Medium:
+		return "medium"
+	case Weak:
+		return "weak"
+	default:
+		return strconv.FormatFloat(float64(s), 'g', -1, 64)
+	}
+}
+
+// RelationOperator relates the expression of a constraint to zero.
 type RelationOperator int
 
+// Relation operators, the zero value is not a valid operator.
 const (
 	RelationOperatorLessThanEqual RelationOperator = iota + 1
 	RelationOperatorEqual
 	RelationOperatorGreaterThanEqual
 )
 
-var _variableID atomic.Uint64
-
-type Variable uint64
-
-func NewVariable() Variable {
-	defer _variableID.Add(1)
-
-	return Variable(_variableID.Load())
+// String returns the operator as written in a constraint, e.g. "<=".
+func (o RelationOperator) String() string {
+	switch o {
+	case RelationOperatorLessThanEqual:
+		return "<="
+	case RelationOperatorEqual:
+		return "=="
+	case RelationOperatorGreaterThanEqual:
+		return ">="
+	default:
+		return "RelationOperator(" + strconv.Itoa(int(o)) + ")"
+	}
 }
 
+// Variable is a dense index of a variable allocated by [Solver.NewVariable].
+type Variable uint32
+
+// Term is a variable multiplied by a coefficient.
 type Term struct {
 	Variable    Variable
 	Coefficient float64
 }
 
+// NewTerm creates the term "variable * coefficient".
 func NewTerm(variable Variable, coefficient float64) Term {
 	return Term{
 		Variable:    variable,
@@ -45,24 +80,30 @@ func NewTerm(variable Variable, coefficient float64) Term {
 	}
 }
 
+// Negate returns the term with the negated coefficient.
 func (t Term) Negate() Term {
 	t.Coefficient = -t.Coefficient
 	return t
 }
 
+// Expression is a linear combination of terms plus a constant.
 type Expression struct {
 	Terms    []Term
 	Constant float64
 }
 
+// NewExpressionFromConstant creates an expression of the constant alone.
 func NewExpressionFromConstant(v float64) Expression {
 	return Expression{Constant: v}
 }
 
+// NewExpressionFromTerm creates an expression of the term alone.
 func NewExpressionFromTerm(term Term) Expression {
 	return Expression{Terms: []Term{term}}
 }
 
+// NewExpression creates the expression "terms + constant".
+// The terms are not copied.
 func NewExpression(constant float64, terms ...Term) Expression {
 	return Expression{
 		Terms:    terms,
@@ -70,6 +111,7 @@ func NewExpression(constant float64, terms ...Term) Expression {
 	}
 }
 
+// Negate returns the expression with all terms and the constant negated.
 func (e Expression) Negate() Expression {
 	e.Terms = slices.Clone(e.Terms)
 	e.Constant = -e.Constant
@@ -81,14 +123,21 @@ func (e Expression) Negate() Expression {
 	return e
 }
 
+// ConstraintData holds an expression related to zero by an operator, with some strength.
 type ConstraintData struct {
 	expression Expression
 	strength   Strength
 	op         RelationOperator
 }
 
+// Constraint is a linear constraint.
+//
+// Constraints are compared by identity: the same constraint value must be
+// used to remove it from the solver, while two equal constraints created
+// separately are distinct.
 type Constraint *ConstraintData
 
+// NewConstraint creates a constraint "e op 0" with the given strength.
 func NewConstraint(e Expression, op RelationOperator, strength Strength) Constraint {
 	data := ConstraintData{
 		expression: e,
@@ -99,23 +148,31 @@ func NewConstraint(e Expression, op RelationOperator, strength Strength) Constra
 	return &data
 }
 
+// Expression returns the expression related to zero.
 func (cd ConstraintData) Expression() Expression {
 	return cd.expression
 }
 
+// Strength returns the strength of the constraint.
 func (cd ConstraintData) Strength() Strength {
 	return cd.strength
 }
 
+// Op returns the operator relating the expression to zero.
 func (cd ConstraintData) Op() RelationOperator {
 	return cd.op
 }
 
+// WeightedRelation is a relation operator together with the strength of
+// the constraint it creates. It is the entry point for building constraints:
+//
+//	casso.Equal(casso.Strong).VariableLHS(x).ConstantRHS(10) // x == 10
 type WeightedRelation struct {
 	Operator RelationOperator
 	Strength Strength
 }
 
+// ExpressionLHS sets the left hand side of the constraint to the expression.
 func (w WeightedRelation) ExpressionLHS(expression Expression) PartialConstraint {
 	return PartialConstraint{
 		Expression: expression,
@@ -123,6 +180,7 @@ func (w WeightedRelation) ExpressionLHS(expression Expression) PartialConstraint
 	}
 }
 
+// VariableLHS sets the left hand side of the constraint to the variable.
 func (w WeightedRelation) VariableLHS(variable Variable) PartialConstraint {
 	return PartialConstraint{
 		Expression: NewExpressionFromTerm(NewTerm(variable, 1)),
@@ -130,23 +188,29 @@ func (w WeightedRelation) VariableLHS(variable Variable) PartialConstraint {
 	}
 }
 
+// Equal creates a "==" relation.
 func Equal(strength Strength) WeightedRelation {
 	return WeightedRelation{Operator: RelationOperatorEqual, Strength: strength}
 }
 
+// LessThanEqual creates a "<=" relation.
 func LessThanEqual(strength Strength) WeightedRelation {
 	return WeightedRelation{Operator: RelationOperatorLessThanEqual, Strength: strength}
 }
 
+// GreaterThanEqual creates a ">=" relation.
 func GreaterThanEqual(strength Strength) WeightedRelation {
 	return WeightedRelation{Operator: RelationOperatorGreaterThanEqual, Strength: strength}
 }
 
+// PartialConstraint is a constraint with the left hand side set,
+// which is completed by providing the right hand side.
 type PartialConstraint struct {
 	Expression Expression
 	Relation   WeightedRelation
 }
 
+// ConstantRHS completes the constraint with the constant on the right hand side.
 func (p PartialConstraint) ConstantRHS(v float64) Constraint {
 	return NewConstraint(
 		p.Expression.SubConstant(v),
@@ -155,6 +219,7 @@ func (p PartialConstraint) ConstantRHS(v float64) Constraint {
 	)
 }
 
+// ExpressionRHS completes the constraint with the expression on the right hand side.
 func (p PartialConstraint) ExpressionRHS(e Expression) Constraint {
 	return NewConstraint(
 		p.Expression.Sub(e),
@@ -163,6 +228,7 @@ func (p PartialConstraint) ExpressionRHS(e Expression) Constraint {
 	)
 }
 
+// VariableRHS completes the constraint with the variable on the right hand side.
 func (p PartialConstraint) VariableRHS(v Variable) Constraint {
 	return NewConstraint(
 		p.Expression.SubVariable(v),
@@ -171,25 +237,25 @@ func (p PartialConstraint) VariableRHS(v Variable) Constraint {
 	)
 }
 
-type SymbolType int
+type _SymbolType int
 
 const (
-	SymbolTypeInvalid SymbolType = iota + 1
-	SymbolTypeExternal
-	SymbolTypeSlack
-	SymbolTypeError
-	SymbolTypeDummy
+	_symbolTypeInvalid _SymbolType = iota + 1
+	_symbolTypeExternal
+	_symbolTypeSlack
+	_symbolTypeError
+	_symbolTypeDummy
 )
 
 type _Symbol struct {
-	Value uint8
-	Type  SymbolType
+	Value uint64
+	Type  _SymbolType
 }
 
 func newInvalidSymbol() _Symbol {
 	return _Symbol{
 		Value: 0,
-		Type:  SymbolTypeInvalid,
+		Type:  _symbolTypeInvalid,
 	}
 }
 
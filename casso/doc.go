@@ -0,0 +1,23 @@
+// Package casso implements the Cassowary linear arithmetic constraint
+// solving algorithm.
+//
+// It is the solver behind uvcasso layouts, but can be used on its own
+// for any problem expressed as linear equalities and inequalities,
+// e.g. aligning columns across separate layouts.
+//
+// Variables are allocated by a [Solver]. Constraints are built from
+// a [WeightedRelation] and expressions of variables:
+//
+//	solver := casso.NewSolver()
+//
+//	left := solver.NewVariable("left")
+//	right := solver.NewVariable("right")
+//
+//	err := solver.AddConstraints(
+//		casso.Equal(casso.Required).VariableLHS(left).ConstantRHS(0),
+//		casso.GreaterThanEqual(casso.Required).VariableLHS(right).ExpressionRHS(casso.NewExpression(10, left.Mul(1))),
+//	)
+//
+// Constraints which are not [Required] may be violated, the solver
+// minimizes their violation weighted by their [Strength].
+package casso
@@ -0,0 +1,61 @@
+package casso
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors returned by the [Solver].
+var (
+	ErrDuplicateConstraint     = errors.New("duplicate constraint")
+	ErrUnsatisfiableConstraint = errors.New("unsatisfiable constraint")
+	ErrUnknownConstraint       = errors.New("unknown constraint")
+	ErrDuplicateEditVariable   = errors.New("duplicate edit variable")
+	ErrBadRequiredStrength     = errors.New("bad required strength")
+	ErrUnknownEditVariable     = errors.New("unknown edit variable")
+	ErrUnknownVariable         = errors.New("unknown variable")
+)
+
+// InternalSolverError is returned when the solver ends up in an inconsistent state.
+// It indicates a bug in the solver rather than in the constraints.
+type InternalSolverError string
+
+func (e InternalSolverError) Error() string {
+	return string(e)
+}
+
+// Conflict is a constraint taking part in an unsatisfiable set of constraints.
+type Conflict struct {
+	Constraint Constraint
+
+	// Description is the constraint rendered with [Solver.FormatConstraint].
+	Description string
+}
+
+// UnsatisfiableConstraintError is returned when a required constraint
+// conflicts with the required constraints already added to the solver.
+//
+// It matches [ErrUnsatisfiableConstraint] with [errors.Is].
+type UnsatisfiableConstraintError struct {
+	// Constraint is the rejected constraint.
+	Constraint Constraint
+
+	// Conflicts is a minimal set of required constraints which can not be
+	// satisfied together, in the order they were added. Removing any of them
+	// makes the rest satisfiable. The rejected constraint is always the last one.
+	Conflicts []Conflict
+}
+
+func (e *UnsatisfiableConstraintError) Error() string {
+	descriptions := make([]string, 0, len(e.Conflicts))
+
+	for _, c := range e.Conflicts {
+		descriptions = append(descriptions, c.Description)
+	}
+
+	return ErrUnsatisfiableConstraint.Error() + ": " + strings.Join(descriptions, "; ")
+}
+
+func (e *UnsatisfiableConstraintError) Unwrap() error {
+	return ErrUnsatisfiableConstraint
+}
@@ -0,0 +1,84 @@
+package casso_test
+
+import (
+	"fmt"
+
+	"github.com/metafates/uvcasso/casso"
+)
+
+// Two columns share a divider: the left column prefers a width of 30,
+// but must leave at least 40 cells to the right one.
+func Example() {
+	solver := casso.NewSolver()
+
+	start := solver.NewVariable("start")
+	divider := solver.NewVariable("divider")
+	end := solver.NewVariable("end")
+
+	err := solver.AddConstraints(
+		casso.Equal(casso.Required).VariableLHS(start).ConstantRHS(0),
+		casso.Equal(casso.Required).VariableLHS(end).ConstantRHS(60),
+		casso.GreaterThanEqual(casso.Required).VariableLHS(divider).VariableRHS(start),
+		casso.GreaterThanEqual(casso.Required).ExpressionLHS(end.Sub(divider)).ConstantRHS(40),
+		casso.Equal(casso.Strong).ExpressionLHS(divider.Sub(start)).ConstantRHS(30),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(solver.GetValue(divider))
+	// Output: 20
+}
+
+// Edit variables are used to move a value interactively,
+// e.g. while the user drags a divider with the mouse.
+func ExampleSolver_SuggestValue() {
+	solver := casso.NewSolver()
+
+	divider := solver.NewVariable("divider")
+
+	err := solver.AddConstraints(
+		casso.GreaterThanEqual(casso.Required).VariableLHS(divider).ConstantRHS(10),
+		casso.LessThanEqual(casso.Required).VariableLHS(divider).ConstantRHS(50),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := solver.AddEditVariable(divider, casso.Strong); err != nil {
+		panic(err)
+	}
+
+	for _, x := range []float64{25, 70, 0} {
+		if err := solver.SuggestValue(divider, x); err != nil {
+			panic(err)
+		}
+
+		fmt.Println(solver.GetValue(divider))
+	}
+	// Output:
+	// 25
+	// 50
+	// 10
+}
+
+// Variables of different layouts can be related in the same solver,
+// here the second column of two tables is aligned.
+func ExampleSolver_AddConstraints() {
+	solver := casso.NewSolver()
+
+	a := solver.NewVariable("a.divider")
+	b := solver.NewVariable("b.divider")
+
+	err := solver.AddConstraints(
+		casso.GreaterThanEqual(casso.Required).VariableLHS(a).ConstantRHS(12),
+		casso.GreaterThanEqual(casso.Required).VariableLHS(b).ConstantRHS(18),
+		casso.Equal(casso.Strong).VariableLHS(a).VariableRHS(b),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(solver.GetValue(a), solver.GetValue(b))
+	// Output: 18 18
+}
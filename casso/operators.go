@@ -0,0 +1,81 @@
+package casso
+
+import "slices"
+
+// Add returns the expression "v + other".
+func (v Variable) Add(other Variable) Expression {
+	return NewExpression(0, NewTerm(v, 1), NewTerm(other, 1))
+}
+
+// Sub returns the expression "v - other".
+func (v Variable) Sub(other Variable) Expression {
+	return NewExpression(0, NewTerm(v, 1), NewTerm(other, -1))
+}
+
+// Mul returns the term "v * coefficient".
+func (v Variable) Mul(coefficient float64) Term {
+	return NewTerm(v, coefficient)
+}
+
+// AddConstant returns the expression "e + other".
+func (e Expression) AddConstant(other float64) Expression {
+	e.Constant += other
+	return e
+}
+
+// SubConstant returns the expression "e - other".
+func (e Expression) SubConstant(other float64) Expression {
+	e.Constant -= other
+	return e
+}
+
+// Add returns the expression "e + other".
+func (e Expression) Add(other Expression) Expression {
+	e.Terms = slices.Concat(e.Terms, other.Terms)
+	e.Constant += other.Constant
+
+	return e
+}
+
+// Sub returns the expression "e - other".
+func (e Expression) Sub(other Expression) Expression {
+	return e.Add(other.Negate())
+}
+
+// AddVariable returns the expression "e + other".
+func (e Expression) AddVariable(other Variable) Expression {
+	e.Terms = slices.Concat(e.Terms, []Term{NewTerm(other, 1)})
+
+	return e
+}
+
+// SubVariable returns the expression "e - other".
+func (e Expression) SubVariable(other Variable) Expression {
+	e.Terms = slices.Concat(e.Terms, []Term{NewTerm(other, -1)})
+
+	return e
+}
+
+// MulConstant returns the expression "e * other".
+func (e Expression) MulConstant(other float64) Expression {
+	e.Terms = slices.Clone(e.Terms)
+	e.Constant *= other
+
+	for i := range e.Terms {
+		e.Terms[i].Coefficient *= other
+	}
+
+	return e
+}
+
+// DivConstant returns the expression "e / other".
+func (e Expression) DivConstant(other float64) Expression {
+	e.Terms = slices.Clone(e.Terms)
+	e.Constant /= other
+
+	for i := range e.Terms {
+		e.Terms[i].Coefficient /= other
+	}
+
+	return e
+}
@@ -0,0 +1,1068 @@
+package casso
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PublicChange is a new value of a variable, see [Solver.FetchChanges].
+type PublicChange struct {
+	// Variable is the variable whose value changed.
+	Variable Variable
+
+	// Constant is the new value of the variable.
+	Constant float64
+}
+
+type _Tag struct {
+	marker _Symbol
+	other  _Symbol
+}
+
+// _VariableNames names variables allocated by [Solver.NewVariables].
+type _VariableNames struct {
+	first Variable
+	count int
+	name  func(i int) string
+}
+
+type _EditInfo struct {
+	tag        _Tag
+	constraint Constraint
+	constant   float64
+}
+
+type _VariableData struct {
+	name     string
+	constant float64
+	symbol   _Symbol
+	count    int
+}
+
+// Solver is an incremental Cassowary constraint solver.
+//
+// The zero value is not usable, create solvers with [NewSolver].
+type Solver struct {
+	cns                map[Constraint]_Tag
+	required           []Constraint
+	varData            []_VariableData
+	names              []_VariableNames
+	varForSymbol       map[_Symbol]Variable
+	publicChanges      []PublicChange
+	changed            map[Variable]struct{}
+	shouldClearChanges bool
+	rows               map[_Symbol]_Row
+	edits              map[Variable]_EditInfo
+	infeasibleRows     []_Symbol
+	objective          _Row
+	artificial         *_Row
+	idTick             uint64
+}
+
+// NewSolver creates a new solver without variables and constraints.
+//
+// The solver must not be copied, it is only used through the returned pointer.
+func NewSolver() *Solver {
+	return &Solver{
+		cns:                make(map[Constraint]_Tag),
+		required:           nil,
+		varData:            nil,
+		varForSymbol:       make(map[_Symbol]Variable),
+		publicChanges:      nil,
+		changed:            make(map[Variable]struct{}),
+		shouldClearChanges: false,
+		rows:               make(map[_Symbol]_Row),
+		edits:              make(map[Variable]_EditInfo),
+		infeasibleRows:     nil,
+		objective:          newRow(0),
+		artificial:         nil,
+		idTick:             1,
+	}
+}
+
+// NewVariable allocates a new variable in the solver.
+//
+// Variables are dense indices local to the solver and must not be used
+// with other solvers. A variable of another solver is only detected when
+// its index is not allocated by this solver, otherwise it silently refers
+// to this solver's variable at that index. The name is only used for
+// debug output and may be empty.
+func (s *Solver) NewVariable(name string) Variable {
+	v := Variable(len(s.varData))
+
+	s.varData = append(s.varData, _VariableData{name: name})
+
+	return v
+}
+
+// NewVariables allocates count consecutive variables in the solver
+// and returns the first one.
+//
+// The name function is called with the position of a variable among them
+// only when its name is needed, see [Solver.VariableName].
+func (s *Solver) NewVariables(count int, name func(i int) string) Variable {
+	first := Variable(len(s.varData))
+
+	s.varData = append(s.varData, make([]_VariableData, count)...)
+
+	if name != nil {
+		s.names = append(s.names, _VariableNames{first: first, count: count, name: name})
+	}
+
+	return first
+}
+
+// VariableCount returns the number of variables allocated in the solver.
+func (s *Solver) VariableCount() int {
+	return len(s.varData)
+}
+
+// VariableName returns the name of the variable.
+// Unnamed variables are named after their index, e.g. "v3".
+func (s *Solver) VariableName(v Variable) string {
+	if int(v) < len(s.varData) && s.varData[v].name != "" {
+		return s.varData[v].name
+	}
+
+	for _, names := range s.names {
+		if v >= names.first && int(v-names.first) < names.count {
+			if name := names.name(int(v - names.first)); name != "" {
+				return name
+			}
+		}
+	}
+
+	return "v" + strconv.FormatUint(uint64(v), 10)
+}
+
+// AddConstraints adds constraints one by one, stopping at the first error.
+func (s *Solver) AddConstraints(constraints ...Constraint) error {
+	for _, c := range constraints {
+		if err := s.AddConstraint(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddConstraint adds a constraint to the solver.
+//
+// Returns [ErrDuplicateConstraint] if the constraint was already added,
+// [ErrUnknownVariable] if it refers to a variable index not allocated by
+// this solver and [*UnsatisfiableConstraintError] if it is a required
+// constraint which conflicts with the required constraints already added.
+func (s *Solver) AddConstraint(constraint Constraint) error {
+	err := s.addConstraint(constraint)
+	if errors.Is(err, ErrUnsatisfiableConstraint) {
+		return s.unsatisfiable(constraint)
+	}
+
+	return err
+}
+
+func (s *Solver) addConstraint(constraint Constraint) error {
+	if _, ok := s.cns[constraint]; ok {
+		return ErrDuplicateConstraint
+	}
+
+	// Checked before the row is created, which references the variables.
+	for _, term := range constraint.expression.Terms {
+		if int(term.Variable) >= len(s.varData) {
+			return fmt.Errorf("%w: %s", ErrUnknownVariable, s.VariableName(term.Variable))
+		}
+	}
+
+	row, tag := s.createRow(constraint)
+	subject := chooseSubject(row, tag)
+
+	if subject.Type == _symbolTypeInvalid && allDummies(row) {
+		if !nearZero(row.constant) {
+			return ErrUnsatisfiableConstraint
+		}
+
+		subject = tag.marker
+	}
+
+	// If an entering symbol still isn't found, then the row must
+	// be added using an artificial variable. If that fails, then
+	// the row represents an unsatisfiable constraint.
+	if subject.Type == _symbolTypeInvalid {
+		ok, err := s.addWithArtificialVariable(row)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return ErrUnsatisfiableConstraint
+		}
+	} else {
+		row.SolveForSymbol(subject)
+		s.substitute(subject, row)
+
+		if subject.Type == _symbolTypeExternal && row.constant != 0 {
+			v := s.varForSymbol[subject]
+			s.varChanged(v)
+		}
+
+		s.rows[subject] = row
+	}
+
+	s.cns[constraint] = tag
+
+	if constraint.strength >= Required {
+		s.required = append(s.required, constraint)
+	}
+
+	if err := s.optimize(&s.objective); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveConstraint removes a constraint previously added to the solver.
+//
+// Returns [ErrUnknownConstraint] if the constraint was not added.
+func (s *Solver) RemoveConstraint(constraint Constraint) error {
+	tag, ok := s.cns[constraint]
+	if !ok {
+		return ErrUnknownConstraint
+	}
+
+	delete(s.cns, constraint)
+
+	if i := slices.Index(s.required, constraint); i >= 0 {
+		s.required = slices.Delete(s.required, i, i+1)
+	}
+
+	// Remove the error effects from the objective function
+	// *before* pivoting, or substitutions into the objective
+	// will lead to incorrect solver results.
+	s.removeConstraintEffects(constraint, tag)
+
+	// If the marker is basic, simply drop the row. Otherwise,
+	// pivot the marker into the basis and then drop the row.
+	if _, ok := s.rows[tag.marker]; ok {
+		delete(s.rows, tag.marker)
+	} else {
+		leaving, row, ok := s.getMarkerLeavingRow(tag.marker)
+		if !ok {
+			return InternalSolverError("failed to find leaving row")
+		}
+
+		row.SolveForSymbols(leaving, tag.marker)
+		s.substitute(tag.marker, row)
+	}
+
+	// Optimizing after each constraint is removed performs less
+	// aggregate work due to a smaller average system size. It
+	// also ensures the solver remains in a consistent state.
+	if err := s.optimize(&s.objective); err != nil {
+		return err
+	}
+
+	// Decrease the reference count of variables referenced by the constraint
+	// and forget the ones which are not referenced anymore.
+	for _, term := range constraint.expression.Terms {
+		if nearZero(term.Coefficient) {
+			continue
+		}
+
+		data := &s.varData[term.Variable]
+		if data.count == 0 {
+			continue
+		}
+
+		data.count--
+
+		if data.count == 0 {
+			delete(s.varForSymbol, data.symbol)
+
+			*data = _VariableData{name: data.name}
+		}
+	}
+
+	return nil
+}
+
+// HasConstraint reports whether the constraint was added to the solver.
+func (s *Solver) HasConstraint(constraint Constraint) bool {
+	_, ok := s.cns[constraint]
+
+	return ok
+}
+
+// AddEditVariable adds an edit variable to the solver.
+//
+// This method should be called before the [Solver.SuggestValue] method is
+// used to supply a suggested value for the given edit variable.
+//
+// Returns [ErrDuplicateEditVariable] if the variable is already an edit variable
+// and [ErrBadRequiredStrength] if the strength is [Required].
+func (s *Solver) AddEditVariable(v Variable, strength Strength) error {
+	if _, ok := s.edits[v]; ok {
+		return ErrDuplicateEditVariable
+	}
+
+	strength = min(max(strength, 0), Required)
+	if strength == Required {
+		return ErrBadRequiredStrength
+	}
+
+	constraint := Equal(strength).VariableLHS(v).ConstantRHS(0)
+
+	if err := s.AddConstraint(constraint); err != nil {
+		return err
+	}
+
+	s.edits[v] = _EditInfo{
+		tag:        s.cns[constraint],
+		constraint: constraint,
+		constant:   0,
+	}
+
+	return nil
+}
+
+// RemoveEditVariable removes an edit variable from the solver.
+//
+// Returns [ErrUnknownEditVariable] if the variable is not an edit variable.
+func (s *Solver) RemoveEditVariable(v Variable) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+
+	delete(s.edits, v)
+
+	return s.RemoveConstraint(info.constraint)
+}
+
+// HasEditVariable reports whether the variable is an edit variable.
+func (s *Solver) HasEditVariable(v Variable) bool {
+	_, ok := s.edits[v]
+
+	return ok
+}
+
+// SuggestValue suggests a value for the given edit variable.
+//
+// This method should be used after an edit variable has been added to
+// the solver in order to suggest the value for that variable.
+//
+// Returns [ErrUnknownEditVariable] if the variable is not an edit variable.
+func (s *Solver) SuggestValue(v Variable, value float64) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+
+	delta := value - info.constant
+	info.constant = value
+	s.edits[v] = info
+
+	// Marker and other symbols of the edit constraint are never external.
+	if row, ok := s.rows[info.tag.marker]; ok {
+		if row.Add(-delta) < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, info.tag.marker)
+		}
+
+		s.rows[info.tag.marker] = row
+
+		return s.dualOptimize()
+	}
+
+	if row, ok := s.rows[info.tag.other]; ok {
+		if row.Add(delta) < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, info.tag.other)
+		}
+
+		s.rows[info.tag.other] = row
+
+		return s.dualOptimize()
+	}
+
+	for symbol, row := range s.rows {
+		coeff := row.CoefficientFor(info.tag.marker)
+		diff := delta * coeff
+
+		if diff != 0 && symbol.Type == _symbolTypeExternal {
+			s.varChanged(s.varForSymbol[symbol])
+		}
+
+		if coeff != 0 && row.Add(diff) < 0 && symbol.Type != _symbolTypeExternal {
+			s.infeasibleRows = append(s.infeasibleRows, symbol)
+		}
+
+		s.rows[symbol] = row
+	}
+
+	return s.dualOptimize()
+}
+
+// FetchChanges fetches all changes to the values of variables since the last call to this function.
+//
+// The list of changes returned is ordered by variable. Each change comprises the variable changed and
+// the new value of that variable.
+func (s *Solver) FetchChanges() []PublicChange {
+	if s.shouldClearChanges {
+		clear(s.changed)
+		s.shouldClearChanges = false
+	} else {
+		s.shouldClearChanges = true
+	}
+
+	s.publicChanges = s.publicChanges[:0]
+
+	for v := range s.changed {
+		varData := &s.varData[v]
+		if varData.count == 0 {
+			continue
+		}
+
+		var newValue float64
+
+		if row, ok := s.rows[varData.symbol]; ok {
+			newValue = row.constant
+		}
+
+		if varData.constant != newValue {
+			s.publicChanges = append(s.publicChanges, PublicChange{
+				Variable: v,
+				Constant: newValue,
+			})
+
+			varData.constant = newValue
+		}
+	}
+
+	slices.SortFunc(s.publicChanges, func(a, b PublicChange) int {
+		return cmp.Compare(a.Variable, b.Variable)
+	})
+
+	return s.publicChanges
+}
+
+// GetValue returns the current value of the variable.
+// Variables not referenced by any constraint have the value of zero.
+func (s *Solver) GetValue(v Variable) float64 {
+	if int(v) >= len(s.varData) {
+		return 0
+	}
+
+	if data := s.varData[v]; data.count > 0 {
+		if row, ok := s.rows[data.symbol]; ok {
+			return row.constant
+		}
+	}
+
+	return 0
+}
+
+// Reset removes all constraints and edit variables.
+// Variables allocated with [Solver.NewVariable] remain valid.
+func (s *Solver) Reset() {
+	clear(s.rows)
+	clear(s.cns)
+	s.required = s.required[:0]
+	// Variables stay allocated, only their state is dropped.
+	for i, data := range s.varData {
+		s.varData[i] = _VariableData{name: data.name}
+	}
+
+	clear(s.varForSymbol)
+	clear(s.changed)
+
+	s.shouldClearChanges = false
+
+	clear(s.edits)
+	s.infeasibleRows = s.infeasibleRows[:0]
+
+	s.objective = newRow(0)
+	s.artificial = nil
+	s.idTick = 1
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func (s *Solver) addWithArtificialVariable(row _Row) (bool, error) {
+	// Create and add the artificial variable to the tableau
+	art := _Symbol{Value: s.idTick, Type: _symbolTypeSlack}
+	s.idTick++
+	s.rows[art] = row.Clone()
+	s.artificial = ptr(row.Clone())
+
+	// Optimize the artificial objective. This is successful
+	// only if the artificial objective is optimized to zero.
+	if err := s.optimize(s.artificial); err != nil {
+		return false, fmt.Errorf("optimize: %w", err)
+	}
+
+	success := nearZero(s.artificial.constant)
+	s.artificial = nil
+
+	if row, ok := s.rows[art]; ok {
+		delete(s.rows, art)
+
+		if len(row.cells) == 0 {
+			return success, nil
+		}
+
+		entering := anyPivotableSymbol(row)
+		if entering.Type == _symbolTypeInvalid {
+			return false, nil
+		}
+
+		row.SolveForSymbols(art, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+
+	// Remove the artificial row from the tableau
+	for symbol, row := range s.rows {
+		row.Remove(art)
+
+		s.rows[symbol] = row
+	}
+
+	s.objective.Remove(art)
+
+	return success, nil
+}
+
+func (s *Solver) optimize(objective *_Row) error {
+	for {
+		entering := getEnteringSymbol(*objective)
+		if entering.Type == _symbolTypeInvalid {
+			return nil
+		}
+
+		leaving, row, ok := s.getLeavingRow(entering)
+		if !ok {
+			return InternalSolverError("unbounded objective")
+		}
+
+		row.SolveForSymbols(leaving, entering)
+
+		s.substitute(entering, row)
+
+		if entering.Type == _symbolTypeExternal && row.constant != 0 {
+			v := s.varForSymbol[entering]
+			s.varChanged(v)
+		}
+		s.rows[entering] = row
+	}
+}
+
+// dualOptimize restores primal feasibility of the tableau by running
+// the dual simplex method over the rows marked as infeasible.
+func (s *Solver) dualOptimize() error {
+	for len(s.infeasibleRows) > 0 {
+		// Rows are marked infeasible in map iteration order,
+		// so always take the lowest one to stay deterministic.
+		slices.SortFunc(s.infeasibleRows, func(a, b _Symbol) int {
+			return cmp.Compare(b.Value, a.Value)
+		})
+
+		leaving := s.infeasibleRows[len(s.infeasibleRows)-1]
+		s.infeasibleRows = s.infeasibleRows[:len(s.infeasibleRows)-1]
+
+		row, ok := s.rows[leaving]
+		if !ok || row.constant >= 0 {
+			continue
+		}
+
+		delete(s.rows, leaving)
+
+		entering := s.getDualEnteringSymbol(row)
+		if entering.Type == _symbolTypeInvalid {
+			return InternalSolverError("dual optimize failed")
+		}
+
+		// Pivot the entering symbol into the basis.
+		row.SolveForSymbols(leaving, entering)
+		s.substitute(entering, row)
+
+		if entering.Type == _symbolTypeExternal && row.constant != 0 {
+			s.varChanged(s.varForSymbol[entering])
+		}
+
+		s.rows[entering] = row
+	}
+
+	return nil
+}
+
+func (s *Solver) varChanged(v Variable) {
+	if s.shouldClearChanges {
+		clear(s.changed)
+		s.shouldClearChanges = false
+	}
+	s.changed[v] = struct{}{}
+}
+
+func (s *Solver) substitute(symbol _Symbol, row _Row) {
+	for otherSymbol, otherRow := range s.rows {
+		constantChanged := otherRow.Substitute(symbol, row)
+		s.rows[otherSymbol] = otherRow
+
+		if otherSymbol.Type == _symbolTypeExternal && constantChanged {
+			v := s.varForSymbol[otherSymbol]
+
+			s.varChanged(v)
+		}
+
+		if otherSymbol.Type != _symbolTypeExternal && otherRow.constant < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, otherSymbol)
+		}
+	}
+
+	s.objective.Substitute(symbol, row)
+
+	if s.artificial != nil {
+		s.artificial.Substitute(symbol, row)
+	}
+}
+
+const _pivotEpsilon = 1e-7
+
+func (s *Solver) getLeavingRow(entering _Symbol) (_Symbol, _Row, bool) {
+	ratio := math.Inf(1)
+
+	var (
+		found _Symbol
+		ok    bool
+	)
+
+	for s, r := range s.rows {
+		if s.Type != _symbolTypeExternal {
+			temp := r.CoefficientFor(entering)
+
+			// Coefficients this small are rounding noise,
+			// pivoting on them blows up the tableau.
+			if temp < -_pivotEpsilon {
+				tempRatio := -r.constant / temp
+
+				// Ties are broken by the lowest symbol (Bland's rule),
+				// which prevents cycling on degenerate pivots.
+				if !ok || tempRatio < ratio-_pivotEpsilon || (tempRatio <= ratio+_pivotEpsilon && s.Value < found.Value) {
+					ratio = tempRatio
+					found = s
+					ok = true
+				}
+			}
+		}
+	}
+
+	if !ok {
+		return _Symbol{}, _Row{}, false
+	}
+
+	row := s.rows[found]
+	delete(s.rows, found)
+
+	return found, row, true
+}
+
+// getDualEnteringSymbol computes the symbol entering the basis for the
+// given infeasible row, using the dual ratio test against the objective.
+func (s *Solver) getDualEnteringSymbol(row _Row) _Symbol {
+	entering := newInvalidSymbol()
+	ratio := math.Inf(1)
+
+	for symbol, value := range row.cells {
+		if value > 0 && symbol.Type != _symbolTypeDummy {
+			r := s.objective.CoefficientFor(symbol) / value
+
+			if r < ratio || (r == ratio && symbol.Value < entering.Value) {
+				ratio = r
+				entering = symbol
+			}
+		}
+	}
+
+	return entering
+}
+
+func (s *Solver) createRow(constraint Constraint) (_Row, _Tag) {
+	expr := constraint.expression
+	row := newRow(expr.Constant)
+
+	for _, term := range expr.Terms {
+		if !nearZero(term.Coefficient) {
+			symbol := s.getVarSymbol(term.Variable)
+
+			if otherRow, ok := s.rows[symbol]; ok {
+				row.InsertRow(otherRow, term.Coefficient)
+			} else {
+				row.InsertSymbol(symbol, term.Coefficient)
+			}
+		}
+	}
+
+	var tag _Tag
+
+	switch constraint.op {
+	case RelationOperatorGreaterThanEqual, RelationOperatorLessThanEqual:
+		coeff := -1.0
+		if constraint.op == RelationOperatorLessThanEqual {
+			coeff = 1.0
+		}
+
+		slack := _Symbol{Value: s.idTick, Type: _symbolTypeSlack}
+		s.idTick++
+
+		row.InsertSymbol(slack, coeff)
+
+		if constraint.strength < Required {
+			errorSymbol := _Symbol{Value: s.idTick, Type: _symbolTypeError}
+			s.idTick++
+
+			row.InsertSymbol(errorSymbol, -coeff)
+			s.objective.InsertSymbol(errorSymbol, float64(constraint.strength))
+
+			tag = _Tag{
+				marker: slack,
+				other:  errorSymbol,
+			}
+		} else {
+			tag = _Tag{
+				marker: slack,
+				other:  newInvalidSymbol(),
+			}
+		}
+	case RelationOperatorEqual:
+		if constraint.strength < Required {
+			errPlus := _Symbol{Value: s.idTick, Type: _symbolTypeError}
+			s.idTick++
+
+			errMinus := _Symbol{Value: s.idTick, Type: _symbolTypeError}
+			s.idTick++
+
+			row.InsertSymbol(errPlus, -1)
+			row.InsertSymbol(errMinus, 1)
+
+			s.objective.InsertSymbol(errPlus, float64(constraint.strength))
+			s.objective.InsertSymbol(errMinus, float64(constraint.strength))
+
+			tag = _Tag{
+				marker: errPlus,
+				other:  errMinus,
+			}
+		} else {
+			dummy := _Symbol{Value: s.idTick, Type: _symbolTypeDummy}
+			s.idTick++
+
+			row.InsertSymbol(dummy, 1)
+
+			tag = _Tag{
+				marker: dummy,
+				other:  newInvalidSymbol(),
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unexpected casso.RelationOperator: %#v", constraint.op))
+	}
+
+	if row.constant < 0 {
+		row.reverseSign()
+	}
+
+	return row, tag
+}
+
+func (s *Solver) getVarSymbol(v Variable) _Symbol {
+	data := &s.varData[v]
+
+	if data.count == 0 {
+		symbol := _Symbol{Value: s.idTick, Type: _symbolTypeExternal}
+		s.varForSymbol[symbol] = v
+		s.idTick++
+
+		data.constant = math.NaN()
+		data.symbol = symbol
+	}
+
+	data.count++
+
+	return data.symbol
+}
+
+func (s *Solver) removeConstraintEffects(constraint Constraint, tag _Tag) {
+	if tag.marker.Type == _symbolTypeError {
+		s.removeMarkerEffects(tag.marker, constraint.strength)
+	} else if tag.other.Type == _symbolTypeError {
+		s.removeMarkerEffects(tag.other, constraint.strength)
+	}
+}
+
+func (s *Solver) removeMarkerEffects(marker _Symbol, strength Strength) {
+	if row, ok := s.rows[marker]; ok {
+		s.objective.InsertRow(row, -float64(strength))
+	} else {
+		s.objective.InsertSymbol(marker, -float64(strength))
+	}
+}
+
+// getMarkerLeavingRow computes the leaving row for a marker symbol
+// which is not basic, and removes it from the tableau.
+func (s *Solver) getMarkerLeavingRow(marker _Symbol) (_Symbol, _Row, bool) {
+	r1 := math.Inf(1)
+	r2 := r1
+
+	var first, second, third _Symbol
+
+	for symbol, row := range s.rows {
+		c := row.CoefficientFor(marker)
+		if c == 0 {
+			continue
+		}
+
+		if symbol.Type == _symbolTypeExternal {
+			if third.Type == 0 || symbol.Value < third.Value {
+				third = symbol
+			}
+		} else if c < 0 {
+			r := -row.constant / c
+
+			if r < r1 || (r == r1 && symbol.Value < first.Value) {
+				r1 = r
+				first = symbol
+			}
+		} else {
+			r := row.constant / c
+
+			if r < r2 || (r == r2 && symbol.Value < second.Value) {
+				r2 = r
+				second = symbol
+			}
+		}
+	}
+
+	var found _Symbol
+
+	switch {
+	case first.Type != 0:
+		found = first
+	case second.Type != 0:
+		found = second
+	case third.Type != 0:
+		found = third
+	default:
+		return _Symbol{}, _Row{}, false
+	}
+
+	row := s.rows[found]
+
+	if found.Type == _symbolTypeExternal && row.constant != 0 {
+		s.varChanged(s.varForSymbol[found])
+	}
+
+	delete(s.rows, found)
+
+	return found, row, true
+}
+
+// chooseSubject chooses the subject for solving the row. The lowest
+// external symbol is preferred, so that the choice does not depend on
+// map iteration order.
+func chooseSubject(row _Row, tag _Tag) _Symbol {
+	subject := newInvalidSymbol()
+
+	for s := range row.cells {
+		if s.Type == _symbolTypeExternal && (subject.Type == _symbolTypeInvalid || s.Value < subject.Value) {
+			subject = s
+		}
+	}
+
+	if subject.Type != _symbolTypeInvalid {
+		return subject
+	}
+
+	for _, s := range []_Symbol{tag.marker, tag.other} {
+		switch s.Type {
+		case _symbolTypeSlack, _symbolTypeError:
+			if row.CoefficientFor(s) < 0 {
+				return s
+			}
+		}
+	}
+
+	return newInvalidSymbol()
+}
+
+func allDummies(row _Row) bool {
+	for s := range row.cells {
+		if s.Type != _symbolTypeDummy {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getEnteringSymbol returns the lowest symbol with a negative
+// coefficient in the objective (Bland's rule).
+func getEnteringSymbol(objective _Row) _Symbol {
+	entering := newInvalidSymbol()
+
+	for s, v := range objective.cells {
+		if s.Type != _symbolTypeDummy && v < 0 {
+			if entering.Type == _symbolTypeInvalid || s.Value < entering.Value {
+				entering = s
+			}
+		}
+	}
+
+	return entering
+}
+
+// anyPivotableSymbol returns the lowest slack or error symbol of the row.
+func anyPivotableSymbol(row _Row) _Symbol {
+	pivotable := newInvalidSymbol()
+
+	for s := range row.cells {
+		switch s.Type {
+		case _symbolTypeSlack, _symbolTypeError:
+			if pivotable.Type == _symbolTypeInvalid || s.Value < pivotable.Value {
+				pivotable = s
+			}
+		}
+	}
+
+	return pivotable
+}
+
+// FormatConstraint renders the constraint using the names of its variables,
+// e.g. "right - left >= 10 (required)".
+func (s *Solver) FormatConstraint(constraint Constraint) string {
+	var (
+		sb       strings.Builder
+		terms    []Term
+		constant = -constraint.expression.Constant
+	)
+
+	if constant == 0 {
+		// Avoid rendering negative zero.
+		constant = 0
+	}
+
+	// Merge the terms of the same variable, keeping the order of their first occurrence.
+	for _, term := range constraint.expression.Terms {
+		i := slices.IndexFunc(terms, func(t Term) bool { return t.Variable == term.Variable })
+		if i < 0 {
+			terms = append(terms, term)
+		} else {
+			terms[i].Coefficient += term.Coefficient
+		}
+	}
+
+	terms = slices.DeleteFunc(terms, func(t Term) bool { return nearZero(t.Coefficient) })
+
+	for i, term := range terms {
+		coefficient := term.Coefficient
+
+		switch {
+		case i == 0 && coefficient < 0:
+			sb.WriteString("-")
+		case i > 0 && coefficient < 0:
+			sb.WriteString(" - ")
+		case i > 0:
+			sb.WriteString(" + ")
+		}
+
+		if coefficient = math.Abs(coefficient); coefficient != 1 {
+			sb.WriteString(strconv.FormatFloat(coefficient, 'g', -1, 64))
+			sb.WriteString("*")
+		}
+
+		sb.WriteString(s.VariableName(term.Variable))
+	}
+
+	if len(terms) == 0 {
+		sb.WriteString("0")
+	}
+
+	fmt.Fprintf(&sb, " %s %s (%s)", constraint.op, strconv.FormatFloat(constant, 'g', -1, 64), constraint.strength)
+
+	return sb.String()
+}
+
+// unsatisfiable explains why the constraint can not be added by finding
+// a minimal set of the required constraints conflicting with it.
+func (s *Solver) unsatisfiable(constraint Constraint) *UnsatisfiableConstraintError {
+	conflict := s.quickXplain([]Constraint{constraint}, false, s.required)
+	conflict = append(conflict, constraint)
+
+	// Keep the insertion order, the rejected constraint goes last.
+	slices.SortStableFunc(conflict, func(a, b Constraint) int {
+		return cmp.Compare(s.requiredIndex(a), s.requiredIndex(b))
+	})
+
+	err := &UnsatisfiableConstraintError{
+		Constraint: constraint,
+		Conflicts:  make([]Conflict, 0, len(conflict)),
+	}
+
+	for _, c := range conflict {
+		err.Conflicts = append(err.Conflicts, Conflict{
+			Constraint:  c,
+			Description: s.FormatConstraint(c),
+		})
+	}
+
+	return err
+}
+
+func (s *Solver) requiredIndex(constraint Constraint) int {
+	if i := slices.Index(s.required, constraint); i >= 0 {
+		return i
+	}
+
+	return len(s.required)
+}
+
+// quickXplain returns a minimal subset of candidates which is unsatisfiable
+// together with the background constraints.
+//
+// See Junker, "QuickXplain: Preferred Explanations and Relaxations for
+// Over-Constrained Problems" (2004).
+func (s *Solver) quickXplain(background []Constraint, checkBackground bool, candidates []Constraint) []Constraint {
+	if checkBackground && !s.satisfiable(background) {
+		return nil
+	}
+
+	if len(candidates) <= 1 {
+		return slices.Clone(candidates)
+	}
+
+	left, right := candidates[:len(candidates)/2], candidates[len(candidates)/2:]
+
+	rightConflict := s.quickXplain(slices.Concat(background, left), true, right)
+	leftConflict := s.quickXplain(slices.Concat(background, rightConflict), len(rightConflict) > 0, left)
+
+	return slices.Concat(leftConflict, rightConflict)
+}
+
+// satisfiable reports whether the constraints can be added to an empty solver
+// with the same variables.
+func (s *Solver) satisfiable(constraints []Constraint) bool {
+	scratch := NewSolver()
+	scratch.varData = make([]_VariableData, len(s.varData))
+
+	for _, c := range constraints {
+		if err := scratch.addConstraint(c); err != nil {
+			return !errors.Is(err, ErrUnsatisfiableConstraint)
+		}
+	}
+
+	return true
+}
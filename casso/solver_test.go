@@ -0,0 +1,399 @@
+package casso
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolverRemoveConstraint(t *testing.T) {
+	t.Run("restores weaker constraint", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		weak := Equal(Weak).VariableLHS(x).ConstantRHS(20)
+		strong := Equal(Strong).VariableLHS(x).ConstantRHS(10)
+
+		require.NoError(t, solver.AddConstraints(weak, strong))
+		require.InDelta(t, 10, solver.GetValue(x), 1e-8)
+
+		require.NoError(t, solver.RemoveConstraint(strong))
+		require.InDelta(t, 20, solver.GetValue(x), 1e-8)
+
+		require.False(t, solver.HasConstraint(strong))
+		require.True(t, solver.HasConstraint(weak))
+	})
+
+	t.Run("required inequality", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		lowerBound := GreaterThanEqual(Required).VariableLHS(x).ConstantRHS(30)
+
+		require.NoError(t, solver.AddConstraints(
+			Equal(Weak).VariableLHS(x).ConstantRHS(20),
+			lowerBound,
+		))
+		require.InDelta(t, 30, solver.GetValue(x), 1e-8)
+
+		require.NoError(t, solver.RemoveConstraint(lowerBound))
+		require.InDelta(t, 20, solver.GetValue(x), 1e-8)
+	})
+
+	t.Run("non-basic marker", func(t *testing.T) {
+		solver := NewSolver()
+		x, y := solver.NewVariable("x"), solver.NewVariable("y")
+
+		sum := Equal(Required).ExpressionLHS(NewExpression(0, NewTerm(x, 1), NewTerm(y, 1))).ConstantRHS(100)
+		pin := Equal(Required).VariableLHS(x).ConstantRHS(40)
+
+		require.NoError(t, solver.AddConstraints(
+			sum,
+			pin,
+			Equal(Weak).VariableLHS(x).ConstantRHS(70),
+		))
+		require.InDelta(t, 40, solver.GetValue(x), 1e-8)
+		require.InDelta(t, 60, solver.GetValue(y), 1e-8)
+
+		require.NoError(t, solver.RemoveConstraint(pin))
+		require.InDelta(t, 70, solver.GetValue(x), 1e-8)
+		require.InDelta(t, 30, solver.GetValue(y), 1e-8)
+
+		require.NoError(t, solver.AddConstraint(pin))
+		require.InDelta(t, 40, solver.GetValue(x), 1e-8)
+		require.InDelta(t, 60, solver.GetValue(y), 1e-8)
+	})
+
+	t.Run("unknown constraint", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		constraint := Equal(Required).VariableLHS(x).ConstantRHS(1)
+
+		require.False(t, solver.HasConstraint(constraint))
+		require.ErrorIs(t, solver.RemoveConstraint(constraint), ErrUnknownConstraint)
+
+		require.NoError(t, solver.AddConstraint(constraint))
+		require.NoError(t, solver.RemoveConstraint(constraint))
+		require.ErrorIs(t, solver.RemoveConstraint(constraint), ErrUnknownConstraint)
+	})
+
+	t.Run("forgets unreferenced variables", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		constraint := Equal(Required).VariableLHS(x).ConstantRHS(5)
+
+		require.NoError(t, solver.AddConstraint(constraint))
+		require.InDelta(t, 5, solver.GetValue(x), 1e-8)
+
+		require.NoError(t, solver.RemoveConstraint(constraint))
+		require.Zero(t, solver.GetValue(x))
+		require.Zero(t, solver.varData[x].count)
+	})
+}
+
+func TestSolverEditVariable(t *testing.T) {
+	t.Run("add and remove", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		require.NoError(t, solver.AddConstraint(Equal(Weak).VariableLHS(x).ConstantRHS(10)))
+
+		require.NoError(t, solver.AddEditVariable(x, Strong))
+		require.True(t, solver.HasEditVariable(x))
+
+		require.NoError(t, solver.RemoveEditVariable(x))
+		require.False(t, solver.HasEditVariable(x))
+		require.InDelta(t, 10, solver.GetValue(x), 1e-8)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		require.ErrorIs(t, solver.AddEditVariable(x, Required), ErrBadRequiredStrength)
+		require.ErrorIs(t, solver.SuggestValue(x, 1), ErrUnknownEditVariable)
+		require.ErrorIs(t, solver.RemoveEditVariable(x), ErrUnknownEditVariable)
+
+		require.NoError(t, solver.AddEditVariable(x, Strong))
+		require.ErrorIs(t, solver.AddEditVariable(x, Medium), ErrDuplicateEditVariable)
+	})
+}
+
+func TestSolverDualOptimize(t *testing.T) {
+	t.Run("suggest value", func(t *testing.T) {
+		solver := NewSolver()
+		left, right, mid := solver.NewVariable("left"), solver.NewVariable("right"), solver.NewVariable("mid")
+
+		require.NoError(t, solver.AddConstraints(
+			Equal(Required).
+				ExpressionLHS(NewExpression(0, NewTerm(mid, 2))).
+				ExpressionRHS(NewExpression(0, NewTerm(left, 1), NewTerm(right, 1))),
+			GreaterThanEqual(Required).VariableLHS(left).ConstantRHS(0),
+			Equal(Weak).VariableLHS(right).ConstantRHS(100),
+		))
+
+		require.NoError(t, solver.AddEditVariable(left, Strong))
+
+		require.NoError(t, solver.SuggestValue(left, 20))
+		require.InDelta(t, 20, solver.GetValue(left), 1e-8)
+		require.InDelta(t, 60, solver.GetValue(mid), 1e-8)
+		require.InDelta(t, 100, solver.GetValue(right), 1e-8)
+
+		require.NoError(t, solver.SuggestValue(left, -40))
+		require.InDelta(t, 0, solver.GetValue(left), 1e-8)
+		require.InDelta(t, 50, solver.GetValue(mid), 1e-8)
+
+		require.NoError(t, solver.SuggestValue(left, 40))
+		require.InDelta(t, 40, solver.GetValue(left), 1e-8)
+		require.InDelta(t, 70, solver.GetValue(mid), 1e-8)
+
+		require.NoError(t, solver.RemoveEditVariable(left))
+		require.InDelta(t, 0, solver.GetValue(left), 1e-8)
+		require.InDelta(t, 50, solver.GetValue(mid), 1e-8)
+	})
+
+	t.Run("stays feasible across repeated edits", func(t *testing.T) {
+		solver := NewSolver()
+
+		// A pane border which can be dragged between 10 and 90.
+		start, border, end := solver.NewVariable("start"), solver.NewVariable("border"), solver.NewVariable("end")
+
+		require.NoError(t, solver.AddConstraints(
+			Equal(Required).VariableLHS(start).ConstantRHS(0),
+			Equal(Required).VariableLHS(end).ConstantRHS(100),
+			GreaterThanEqual(Required).VariableLHS(border).ExpressionRHS(NewExpression(10, NewTerm(start, 1))),
+			LessThanEqual(Required).VariableLHS(border).ExpressionRHS(NewExpression(-10, NewTerm(end, 1))),
+			Equal(Weak).VariableLHS(border).ConstantRHS(50),
+		))
+
+		require.NoError(t, solver.AddEditVariable(border, Strong))
+
+		for i := range 1000 {
+			suggested := float64((i*37)%140 - 20)
+
+			require.NoError(t, solver.SuggestValue(border, suggested))
+
+			want := min(max(suggested, 10), 90)
+
+			require.InDelta(t, want, solver.GetValue(border), 1e-6)
+			require.InDelta(t, 0, solver.GetValue(start), 1e-6)
+			require.InDelta(t, 100, solver.GetValue(end), 1e-6)
+
+			require.Empty(t, solver.infeasibleRows)
+
+			for symbol, row := range solver.rows {
+				if symbol.Type != _symbolTypeExternal {
+					require.GreaterOrEqual(t, row.constant, -1e-8)
+				}
+			}
+		}
+	})
+
+	t.Run("fetch changes", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		require.NoError(t, solver.AddEditVariable(x, Strong))
+
+		for _, value := range []float64{10, 20, 20, 5} {
+			require.NoError(t, solver.SuggestValue(x, value))
+
+			for _, change := range solver.FetchChanges() {
+				require.Equal(t, x, change.Variable)
+				require.InDelta(t, value, change.Constant, 1e-8)
+			}
+		}
+	})
+}
+
+func TestSolverVariables(t *testing.T) {
+	solver := NewSolver()
+
+	x := solver.NewVariable("x")
+	y := solver.NewVariable("")
+
+	require.Equal(t, Variable(0), x)
+	require.Equal(t, Variable(1), y)
+	require.Equal(t, 2, solver.VariableCount())
+
+	require.Equal(t, "x", solver.VariableName(x))
+	require.Equal(t, "v1", solver.VariableName(y))
+
+	require.NoError(t, solver.AddConstraint(Equal(Required).VariableLHS(x).ConstantRHS(3)))
+	require.InDelta(t, 3, solver.GetValue(x), 1e-8)
+
+	solver.Reset()
+
+	require.Equal(t, 2, solver.VariableCount())
+	require.Equal(t, "x", solver.VariableName(x))
+	require.Zero(t, solver.GetValue(x))
+
+	require.NoError(t, solver.AddConstraint(Equal(Required).VariableLHS(x).ConstantRHS(4)))
+	require.InDelta(t, 4, solver.GetValue(x), 1e-8)
+}
+
+func TestSolverNewVariables(t *testing.T) {
+	solver := NewSolver()
+
+	x := solver.NewVariable("x")
+
+	var calls int
+
+	first := solver.NewVariables(3, func(i int) string {
+		calls++
+
+		if i == 2 {
+			return ""
+		}
+
+		return "p" + string(rune('a'+i))
+	})
+
+	require.Equal(t, Variable(1), first)
+	require.Equal(t, 4, solver.VariableCount())
+	require.Zero(t, calls)
+
+	require.Equal(t, "x", solver.VariableName(x))
+	require.Equal(t, "pb", solver.VariableName(first+1))
+	require.Equal(t, "v3", solver.VariableName(first+2))
+	require.Equal(t, 2, calls)
+
+	require.NoError(t, solver.AddConstraint(Equal(Required).VariableLHS(first+2).ConstantRHS(5)))
+	require.InDelta(t, 5, solver.GetValue(first+2), 1e-8)
+}
+
+func TestSolverUnknownVariable(t *testing.T) {
+	// The index of the foreign variable is not allocated by the solver.
+	other := NewSolver()
+	other.NewVariable("a")
+	foreign := other.NewVariable("b")
+
+	solver := NewSolver()
+	x := solver.NewVariable("x")
+
+	err := solver.AddConstraint(Equal(Required).VariableLHS(x).VariableRHS(foreign))
+	require.ErrorIs(t, err, ErrUnknownVariable)
+
+	require.ErrorIs(t, solver.AddEditVariable(foreign, Strong), ErrUnknownVariable)
+
+	// The solver is left untouched.
+	require.NoError(t, solver.AddConstraint(Equal(Required).VariableLHS(x).ConstantRHS(2)))
+	require.InDelta(t, 2, solver.GetValue(x), 1e-8)
+}
+
+func TestSolverUnsatisfiable(t *testing.T) {
+	descriptions := func(err *UnsatisfiableConstraintError) []string {
+		var result []string
+
+		for _, c := range err.Conflicts {
+			result = append(result, c.Description)
+		}
+
+		return result
+	}
+
+	t.Run("direct conflict", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+		y := solver.NewVariable("y")
+
+		rejected := LessThanEqual(Required).VariableLHS(x).ConstantRHS(5)
+
+		require.NoError(t, solver.AddConstraints(
+			GreaterThanEqual(Required).VariableLHS(x).ConstantRHS(10),
+			Equal(Required).VariableLHS(y).ConstantRHS(3),
+			Equal(Strong).VariableLHS(x).ConstantRHS(20),
+		))
+
+		err := solver.AddConstraint(rejected)
+
+		var target *UnsatisfiableConstraintError
+
+		require.ErrorAs(t, err, &target)
+		require.ErrorIs(t, err, ErrUnsatisfiableConstraint)
+		require.Equal(t, rejected, target.Constraint)
+		require.Equal(t, []string{"x >= 10 (required)", "x <= 5 (required)"}, descriptions(target))
+		require.Equal(t, "unsatisfiable constraint: x >= 10 (required); x <= 5 (required)", err.Error())
+
+		require.False(t, solver.HasConstraint(rejected))
+	})
+
+	t.Run("minimal chain", func(t *testing.T) {
+		solver := NewSolver()
+		a := solver.NewVariable("a")
+		b := solver.NewVariable("b")
+		c := solver.NewVariable("c")
+		d := solver.NewVariable("d")
+
+		require.NoError(t, solver.AddConstraints(
+			LessThanEqual(Required).VariableLHS(a).VariableRHS(b),
+			LessThanEqual(Required).VariableLHS(d).VariableRHS(c),
+			LessThanEqual(Required).VariableLHS(b).VariableRHS(c),
+			GreaterThanEqual(Required).VariableLHS(d).ConstantRHS(0),
+			Equal(Required).VariableLHS(a).ConstantRHS(10),
+		))
+
+		err := solver.AddConstraint(Equal(Required).VariableLHS(c).ConstantRHS(5))
+
+		var target *UnsatisfiableConstraintError
+
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, []string{
+			"a - b <= 0 (required)",
+			"b - c <= 0 (required)",
+			"a == 10 (required)",
+			"c == 5 (required)",
+		}, descriptions(target))
+	})
+
+	t.Run("removed constraints are not reported", func(t *testing.T) {
+		solver := NewSolver()
+		x := solver.NewVariable("x")
+
+		removed := GreaterThanEqual(Required).VariableLHS(x).ConstantRHS(10)
+
+		require.NoError(t, solver.AddConstraint(removed))
+		require.NoError(t, solver.RemoveConstraint(removed))
+		require.NoError(t, solver.AddConstraint(Equal(Required).VariableLHS(x).ConstantRHS(0)))
+
+		err := solver.AddConstraint(Equal(Required).VariableLHS(x).ConstantRHS(1))
+
+		var target *UnsatisfiableConstraintError
+
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, []string{"x == 0 (required)", "x == 1 (required)"}, descriptions(target))
+	})
+}
+
+func TestSolverFormatConstraint(t *testing.T) {
+	solver := NewSolver()
+	left := solver.NewVariable("left")
+	right := solver.NewVariable("right")
+	unnamed := solver.NewVariable("")
+
+	testCases := []struct {
+		constraint Constraint
+		want       string
+	}{
+		{
+			constraint: GreaterThanEqual(Required).VariableLHS(right).ExpressionRHS(left.Sub(unnamed).AddConstant(10)),
+			want:       "right - left + v2 >= 10 (required)",
+		},
+		{
+			constraint: Equal(Strong).ExpressionLHS(NewExpression(0, left.Mul(2), right.Mul(-0.5), left.Mul(1))).ConstantRHS(-3),
+			want:       "3*left - 0.5*right == -3 (strong)",
+		},
+		{
+			constraint: LessThanEqual(Strong * 10).VariableLHS(left).VariableRHS(left),
+			want:       "0 <= 0 (1e+07)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			require.Equal(t, tc.want, solver.FormatConstraint(tc.constraint))
+		})
+	}
+}
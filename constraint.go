@@ -1,6 +1,11 @@
 package uvcasso
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/metafates/uvcasso/casso"
+)
 
 type Constraint interface {
 	fmt.Stringer
@@ -15,6 +20,14 @@ type (
 	Percentage int
 	Ratio      struct{ Num, Den int }
 	Fill       int
+
+	// Auto sizes the segment to its content. The function is called with
+	// the size of the area along the cross axis, e.g. the width for a vertical
+	// layout, and returns the preferred size along the main axis.
+	//
+	// Auto segments give way before [Len] does, but are preferred over
+	// [Percentage], [Ratio] and [Fill].
+	Auto func(cross int) int
 )
 
 func (m Min) String() string { return fmt.Sprintf("Min(%d)", m) }
@@ -34,3 +47,148 @@ func (Ratio) isConstraint()    {}
 
 func (f Fill) String() string { return fmt.Sprintf("Fill(%d)", f) }
 func (Fill) isConstraint()    {}
+
+func (Auto) String() string { return "Auto" }
+func (Auto) isConstraint()  {}
+
+// _AutoLen is the measured size of an [Auto] constraint.
+type _AutoLen int
+
+func (a _AutoLen) String() string { return fmt.Sprintf("Auto(%d)", a) }
+func (_AutoLen) isConstraint()    {}
+
+// Calc sizes the segment as a fraction of the area plus a constant:
+//
+//	size == area * Num / Den + Offset
+//
+// e.g. Calc{Num: 1, Den: 2, Offset: -2} is half of the area minus 2 cells.
+// The size is clamped to Min and Max, a zero Max means no upper bound.
+// Den less than 1 is treated as 1.
+type Calc struct {
+	Num, Den int
+	Offset   int
+	Min, Max int
+}
+
+func (c Calc) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Calc(%d/%d", c.Num, c.Den)
+
+	switch {
+	case c.Offset > 0:
+		fmt.Fprintf(&b, " + %d", c.Offset)
+	case c.Offset < 0:
+		fmt.Fprintf(&b, " - %d", -c.Offset)
+	}
+
+	if c.Min != 0 {
+		fmt.Fprintf(&b, ", Min(%d)", c.Min)
+	}
+
+	if c.Max != 0 {
+		fmt.Fprintf(&b, ", Max(%d)", c.Max)
+	}
+
+	b.WriteString(")")
+
+	return b.String()
+}
+func (Calc) isConstraint() {}
+
+// Relative sizes the segment relative to the segment at Index of the same layout:
+//
+//	size == size(Index) * Scale
+//
+// A zero Scale is treated as 1. When Strength is zero, the relation wins over
+// [Len] but gives way to [Min] and [Max]. An Index outside of the layout
+// makes [Layout.TrySplit] return an error wrapping [ErrUnknownSegment].
+type Relative struct {
+	Index    int
+	Scale    float64
+	Strength casso.Strength
+}
+
+func (r Relative) String() string {
+	return fmt.Sprintf("Relative(%d, %g, %g)", r.Index, r.Scale, float64(r.Strength))
+}
+func (Relative) isConstraint() {}
+
+// Grouped adds the segment to a group of segments sharing one size,
+// while the segment is otherwise sized by the wrapped Constraint.
+//
+// When Strength is zero, the group wins over [Len]
+// but gives way to [Min] and [Max].
+type Grouped struct {
+	Constraint Constraint
+	Group      string
+	Strength   casso.Strength
+}
+
+func (g Grouped) String() string {
+	return fmt.Sprintf("Grouped(%v, %q, %g)", g.Constraint, g.Group, float64(g.Strength))
+}
+func (Grouped) isConstraint() {}
+
+func (g Grouped) unwrap() Constraint { return g.Constraint }
+
+func (g Grouped) wrap(c Constraint) Constraint {
+	g.Constraint = c
+	return g
+}
+
+// Hidden collapses the segment to an empty one and removes the spacing
+// around it, while the segment keeps its index in the split result.
+// The wrapped Constraint is ignored until the segment is shown again.
+// A hidden segment wrapped by [Grouped] takes no part in its group.
+type Hidden struct {
+	Constraint Constraint
+}
+
+func (h Hidden) String() string { return fmt.Sprintf("Hidden(%v)", h.Constraint) }
+func (Hidden) isConstraint()    {}
+
+// isHidden reports whether the constraint is [Hidden], possibly wrapped
+// by other constraints, e.g. [Grouped] or [Prioritized].
+func isHidden(c Constraint) bool {
+	for {
+		switch w := c.(type) {
+		case Hidden:
+			return true
+		case _wrapper:
+			c = w.unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// _wrapper is implemented by constraints modifying another constraint.
+type _wrapper interface {
+	Constraint
+
+	unwrap() Constraint
+	wrap(c Constraint) Constraint
+}
+
+// baseConstraint returns the constraint without wrappers.
+func baseConstraint(c Constraint) Constraint {
+	for {
+		w, ok := c.(_wrapper)
+		if !ok {
+			return c
+		}
+
+		c = w.unwrap()
+	}
+}
+
+// replaceBase returns the constraint with the base constraint
+// replaced by the given one, keeping the wrappers.
+func replaceBase(c, base Constraint) Constraint {
+	if w, ok := c.(_wrapper); ok {
+		return w.wrap(replaceBase(w.unwrap(), base))
+	}
+
+	return base
+}
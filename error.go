@@ -0,0 +1,126 @@
+package uvcasso
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/metafates/uvcasso/casso"
+)
+
+// ErrPlacementOutOfBounds is returned when a grid placement
+// covers cells outside of the grid.
+var ErrPlacementOutOfBounds = errors.New("uvcasso: placement out of bounds")
+
+// OverlappingPlacementError is returned when two grid placements cover the same cell.
+type OverlappingPlacementError struct {
+	// Index is the index of the placement which overlaps the Other one.
+	Index, Other int
+
+	// Row and Column are the first cell covered by both placements.
+	Row, Column int
+}
+
+func (e *OverlappingPlacementError) Error() string {
+	return fmt.Sprintf(
+		"uvcasso: placement %d overlaps placement %d at row %d, column %d",
+		e.Index, e.Other, e.Row, e.Column,
+	)
+}
+
+// ErrDuplicateNodeID is returned when several nodes of a tree have the same ID.
+var ErrDuplicateNodeID = errors.New("uvcasso: duplicate node id")
+
+// ErrTooManyChildren is returned when a node has more children than
+// its layout has constraints.
+var ErrTooManyChildren = errors.New("uvcasso: too many children")
+
+// ErrDuplicateLayoutName is returned when several layouts of a system have the same name.
+var ErrDuplicateLayoutName = errors.New("uvcasso: duplicate layout name")
+
+// ErrUnknownSegment is returned when a relation refers to a segment
+// which is not part of the system, or a [Relative] constraint refers
+// to a segment which is not part of its layout.
+var ErrUnknownSegment = errors.New("uvcasso: unknown segment")
+
+// ErrInvalidRelation is returned when a relation has no valid operator
+// or its Num is less than 1, e.g. the zero [Relation].
+var ErrInvalidRelation = errors.New("uvcasso: invalid relation")
+
+// ErrTemplateMismatch is returned when the number of grid rows or columns
+// does not match the template.
+var ErrTemplateMismatch = errors.New("uvcasso: template does not match grid")
+
+// TemplateError is returned when a grid template can not be parsed.
+// Line and Column are 1-based and point at the offending part of the template.
+type TemplateError struct {
+	Line, Column int
+	Message      string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("uvcasso: template %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// GapError is returned when a gap of a layout is sized by a constraint
+// which only applies to segments, e.g. [Auto] or [Relative].
+type GapError struct {
+	// Index is the index of the gap in [Layout.Gaps].
+	Index      int
+	Constraint Constraint
+}
+
+func (e *GapError) Error() string {
+	return fmt.Sprintf("uvcasso: gap %d: unsupported constraint %s", e.Index, e.Constraint)
+}
+
+// UnsatisfiableConstraintError is returned when the constraints of a layout
+// can not be satisfied together.
+//
+// It wraps [casso.ErrUnsatisfiableConstraint] and, when available, the
+// [*casso.UnsatisfiableConstraintError] describing the minimal set of
+// conflicting solver constraints. The conflict is not mapped back to the
+// layout constraints, use [errors.As] to get the explanation.
+type UnsatisfiableConstraintError struct {
+	Err error
+}
+
+func (e *UnsatisfiableConstraintError) Error() string {
+	return "uvcasso: " + e.Err.Error()
+}
+
+func (e *UnsatisfiableConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// InternalSolverError is returned when the constraint solver
+// ends up in an inconsistent state.
+//
+// It wraps [casso.InternalSolverError].
+type InternalSolverError struct {
+	Err error
+}
+
+func (e *InternalSolverError) Error() string {
+	return "uvcasso: internal solver error: " + e.Err.Error()
+}
+
+func (e *InternalSolverError) Unwrap() error {
+	return e.Err
+}
+
+func wrapSolverError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, casso.ErrUnsatisfiableConstraint) {
+		return &UnsatisfiableConstraintError{Err: err}
+	}
+
+	var internal casso.InternalSolverError
+	if errors.As(err, &internal) {
+		return &InternalSolverError{Err: err}
+	}
+
+	return err
+}
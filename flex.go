@@ -9,4 +9,7 @@ const (
 	FlexCenter
 	FlexSpaceBetween
 	FlexSpaceAround
+	// FlexSpaceEvenly distributes the excess space evenly between the segments,
+	// before the first one and after the last one.
+	FlexSpaceEvenly
 )
@@ -0,0 +1,236 @@
+package uvcasso
+
+import (
+	"fmt"
+	"math"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// FlowItem is a segment placed by a [Flow].
+type FlowItem struct {
+	Rect uv.Rectangle
+
+	// Line is the index of the line the segment is placed on.
+	Line int
+}
+
+// Flow places segments along the main axis and wraps them
+// onto further lines along the cross axis when a line overflows.
+//
+// Each line is split by a [Layout] with the Flex and Spacing of the flow,
+// lines are sized by LineSize and separated by LineSpacing.
+type Flow struct {
+	Direction   Direction
+	Constraints []Constraint
+	Padding     Padding
+	Spacing     Spacing
+	Flex        Flex
+
+	// LineSize sizes every line along the cross axis. [Auto] and [Measured]
+	// segments are measured against the size of a line when breaking lines,
+	// except for [Fill] and [Min] line sizes, which share the cross axis
+	// between the lines and are measured against the whole cross axis.
+	LineSize    Constraint
+	LineSpacing Spacing
+
+	// Cache stores split results of the lines.
+	// When nil, the global cache is used.
+	Cache *Cache
+}
+
+// HorizontalFlow creates a flow placing segments left to right
+// and wrapping them onto lines below.
+func HorizontalFlow(constraints ...Constraint) Flow {
+	return NewFlow(DirectionHorizontal, constraints...)
+}
+
+// VerticalFlow creates a flow placing segments top to bottom
+// and wrapping them onto columns to the right.
+func VerticalFlow(constraints ...Constraint) Flow {
+	return NewFlow(DirectionVertical, constraints...)
+}
+
+func NewFlow(direction Direction, constraints ...Constraint) Flow {
+	return Flow{
+		Direction:   direction,
+		Constraints: constraints,
+		Padding:     NewPadding(),
+		Spacing:     SpacingSpace(0),
+		Flex:        FlexStart,
+		LineSize:    Len(1),
+		LineSpacing: SpacingSpace(0),
+	}
+}
+
+func (f Flow) WithPadding(padding Padding) Flow {
+	f.Padding = padding
+	return f
+}
+
+func (f Flow) WithSpacing(spacing Spacing) Flow {
+	f.Spacing = spacing
+	return f
+}
+
+func (f Flow) WithFlex(flex Flex) Flow {
+	f.Flex = flex
+	return f
+}
+
+func (f Flow) WithLineSize(size Constraint) Flow {
+	f.LineSize = size
+	return f
+}
+
+func (f Flow) WithLineSpacing(spacing Spacing) Flow {
+	f.LineSpacing = spacing
+	return f
+}
+
+func (f Flow) WithCache(cache *Cache) Flow {
+	f.Cache = cache
+	return f
+}
+
+// Lines returns the indexes of the segments on each line when
+// the main axis of the inner area is of the given size.
+//
+// A segment goes onto a new line when the line would overflow with it.
+// Segments are measured by their basis: the size of [Len], [Min], [Max],
+// the share of the main size of [Percentage], [Ratio] and [Calc], and the
+// preferred size of [Auto] and [Measured]. [Fill] has no basis, it takes
+// the space left on its line.
+func (f Flow) Lines(main, cross int) [][]int {
+	spacing := spacingSize(f.Spacing)
+
+	var (
+		lines   [][]int
+		line    []int
+		visible int
+		size    int
+	)
+
+	for i, c := range f.Constraints {
+		// Hidden segments take neither space nor spacing, they stay on the current line.
+		if isHidden(c) {
+			line = append(line, i)
+			continue
+		}
+
+		basis := flowBasis(c, f.Direction, main, cross)
+
+		if visible > 0 && size+spacing+basis > main {
+			lines = append(lines, line)
+			line, visible, size = nil, 0, 0
+		}
+
+		if visible > 0 {
+			size += spacing
+		}
+
+		line = append(line, i)
+		visible++
+		size += basis
+	}
+
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// TrySplit splits the given area into segments placed on lines.
+//
+// See [Layout.TrySplit] for the returned errors.
+func (f Flow) TrySplit(area uv.Rectangle) ([]FlowItem, error) {
+	innerArea := f.Padding.Apply(area)
+
+	crossDirection := DirectionHorizontal
+	main, cross := innerArea.Dy(), innerArea.Dx()
+
+	if f.Direction == DirectionHorizontal {
+		crossDirection = DirectionVertical
+		main, cross = innerArea.Dx(), innerArea.Dy()
+	}
+
+	lines := f.Lines(main, crossSize(f.LineSize, crossDirection, cross, main))
+
+	lineConstraints := make([]Constraint, len(lines))
+	for i := range lineConstraints {
+		lineConstraints[i] = f.LineSize
+	}
+
+	lineAreas, _, err := New(crossDirection, lineConstraints...).
+		WithFlex(FlexStart).
+		WithSpacing(f.LineSpacing).
+		WithCache(f.Cache).
+		TrySplit(innerArea)
+	if err != nil {
+		return nil, fmt.Errorf("split lines: %w", err)
+	}
+
+	items := make([]FlowItem, len(f.Constraints))
+
+	for lineIndex, line := range lines {
+		constraints := make([]Constraint, len(line))
+		for i, index := range line {
+			constraints[i] = f.Constraints[index]
+		}
+
+		segments, _, err := New(f.Direction, constraints...).
+			WithFlex(f.Flex).
+			WithSpacing(f.Spacing).
+			WithCache(f.Cache).
+			TrySplit(lineAreas[lineIndex])
+		if err != nil {
+			return nil, fmt.Errorf("split line %d: %w", lineIndex, err)
+		}
+
+		for i, index := range line {
+			items[index] = FlowItem{
+				Rect: segments[i],
+				Line: lineIndex,
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// Split splits the given area into segments placed on lines.
+//
+// It panics if the constraints can not be solved, see [Flow.TrySplit].
+func (f Flow) Split(area uv.Rectangle) []FlowItem {
+	items, err := f.TrySplit(area)
+	if err != nil {
+		panic(err)
+	}
+
+	return items
+}
+
+// flowBasis returns the size of the segment used to break lines.
+func flowBasis(c Constraint, direction Direction, main, cross int) int {
+	switch c := baseConstraint(c).(type) {
+	case Percentage:
+		return main * int(c) / 100
+
+	case Ratio:
+		return main * c.Num / max(1, c.Den)
+
+	case Calc:
+		size := main*c.Num/max(1, c.Den) + c.Offset
+
+		upper := c.Max
+		if upper == 0 {
+			upper = math.MaxInt
+		}
+
+		return max(0, c.Min, min(size, upper))
+
+	default:
+		return measureConstraint(c, direction, cross).Preferred
+	}
+}
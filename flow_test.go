@@ -0,0 +1,127 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlow(t *testing.T) {
+	t.Run("wraps buttons", func(t *testing.T) {
+		flow := HorizontalFlow(Len(12), Len(12), Len(12), Len(12), Len(12)).
+			WithSpacing(SpacingSpace(1)).
+			WithLineSpacing(SpacingSpace(1))
+
+		require.Equal(t, []FlowItem{
+			{Rect: uv.Rect(0, 0, 12, 1), Line: 0},
+			{Rect: uv.Rect(13, 0, 12, 1), Line: 0},
+			{Rect: uv.Rect(0, 2, 12, 1), Line: 1},
+			{Rect: uv.Rect(13, 2, 12, 1), Line: 1},
+			{Rect: uv.Rect(0, 4, 12, 1), Line: 2},
+		}, flow.Split(uv.Rect(0, 0, 30, 10)))
+	})
+
+	t.Run("fits on one line", func(t *testing.T) {
+		flow := HorizontalFlow(Len(4), Len(4), Len(4)).WithSpacing(SpacingSpace(1))
+
+		require.Equal(t, [][]int{{0, 1, 2}}, flow.Lines(14, 1))
+		require.Equal(t, [][]int{{0, 1}, {2}}, flow.Lines(13, 1))
+	})
+
+	t.Run("justified lines", func(t *testing.T) {
+		flow := HorizontalFlow(Len(4), Len(4), Len(4)).
+			WithFlex(FlexCenter).
+			WithLineSize(Len(2))
+
+		require.Equal(t, []FlowItem{
+			{Rect: uv.Rect(1, 0, 4, 2), Line: 0},
+			{Rect: uv.Rect(5, 0, 4, 2), Line: 0},
+			{Rect: uv.Rect(3, 2, 4, 2), Line: 1},
+		}, flow.Split(uv.Rect(0, 0, 10, 10)))
+	})
+
+	t.Run("fill takes the rest of the line", func(t *testing.T) {
+		flow := HorizontalFlow(Len(6), Fill(1), Len(6))
+
+		require.Equal(t, [][]int{{0, 1}, {2}}, flow.Lines(10, 1))
+
+		items := flow.Split(uv.Rect(0, 0, 10, 2))
+
+		require.Equal(t, uv.Rect(6, 0, 4, 1), items[1].Rect)
+		require.Equal(t, uv.Rect(0, 1, 6, 1), items[2].Rect)
+	})
+
+	t.Run("vertical", func(t *testing.T) {
+		flow := VerticalFlow(Len(3), Len(3), Percentage(25)).
+			WithLineSize(Len(5)).
+			WithPadding(NewPadding(1))
+
+		require.Equal(t, []FlowItem{
+			{Rect: uv.Rect(1, 1, 5, 3), Line: 0},
+			{Rect: uv.Rect(6, 1, 5, 3), Line: 1},
+			{Rect: uv.Rect(6, 4, 5, 1), Line: 1},
+		}, flow.Split(uv.Rect(0, 0, 20, 6)))
+	})
+
+	t.Run("measured with line size", func(t *testing.T) {
+		var got []int
+
+		label := Auto(func(cross int) int {
+			got = append(got, cross)
+			return 5
+		})
+
+		flow := HorizontalFlow(label, label, label).WithLineSize(Len(2)).WithCache(NewCache(0))
+
+		require.Equal(t, [][]int{{0, 1}, {2}}, flow.Lines(12, 2))
+
+		items := flow.Split(uv.Rect(0, 0, 12, 4))
+
+		require.Equal(t, uv.Rect(0, 2, 5, 2), items[2].Rect)
+		require.NotEmpty(t, got)
+
+		for _, cross := range got {
+			require.Equal(t, 2, cross)
+		}
+	})
+
+	t.Run("measured with resolved line size", func(t *testing.T) {
+		for _, tc := range []struct {
+			size  Constraint
+			cross int
+		}{
+			{size: Percentage(50), cross: 3},
+			{size: Calc{Num: 1, Den: 3, Offset: 1}, cross: 3},
+			{size: Max(2), cross: 2},
+			{size: Fill(1), cross: 6},
+		} {
+			t.Run(tc.size.String(), func(t *testing.T) {
+				var got []int
+
+				label := Auto(func(cross int) int {
+					got = append(got, cross)
+					return 5
+				})
+
+				flow := HorizontalFlow(label, label, label).WithLineSize(tc.size).WithCache(NewCache(0))
+
+				flow.Split(uv.Rect(0, 0, 12, 6))
+
+				require.NotEmpty(t, got)
+				require.Equal(t, tc.cross, got[0])
+			})
+		}
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		flow := HorizontalFlow(Len(4), Hidden{Len(4)}, Len(4)).WithSpacing(SpacingSpace(1))
+
+		require.Equal(t, [][]int{{0, 1, 2}}, flow.Lines(9, 1))
+
+		items := flow.Split(uv.Rect(0, 0, 9, 1))
+
+		require.Equal(t, uv.Rect(4, 0, 0, 1), items[1].Rect)
+		require.Equal(t, uv.Rect(5, 0, 4, 1), items[2].Rect)
+	})
+}
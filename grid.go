@@ -0,0 +1,279 @@
+package uvcasso
+
+import (
+	"fmt"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// Cells is a matrix of grid cells indexed by row and then by column.
+type Cells [][]uv.Rectangle
+
+// Cell returns the cell at the given row and column.
+func (c Cells) Cell(row, column int) uv.Rectangle {
+	return c[row][column]
+}
+
+// Row returns the cells of the given row.
+func (c Cells) Row(row int) Splitted {
+	return c[row]
+}
+
+// Column returns the cells of the given column.
+func (c Cells) Column(column int) Splitted {
+	cells := make(Splitted, 0, len(c))
+
+	for _, row := range c {
+		cells = append(cells, row[column])
+	}
+
+	return cells
+}
+
+// Placement places a tile over a range of grid cells,
+// starting at Row and Column and spanning RowSpan rows and ColumnSpan columns.
+//
+// Spans less than 1 are treated as 1.
+type Placement struct {
+	Row, Column         int
+	RowSpan, ColumnSpan int
+}
+
+// Place creates a placement covering a single cell.
+func Place(row, column int) Placement {
+	return Placement{
+		Row:        row,
+		Column:     column,
+		RowSpan:    1,
+		ColumnSpan: 1,
+	}
+}
+
+func (p Placement) WithSpan(rows, columns int) Placement {
+	p.RowSpan = rows
+	p.ColumnSpan = columns
+
+	return p
+}
+
+func (p Placement) WithRowSpan(rows int) Placement {
+	p.RowSpan = rows
+	return p
+}
+
+func (p Placement) WithColumnSpan(columns int) Placement {
+	p.ColumnSpan = columns
+	return p
+}
+
+func (p Placement) lastRow() int {
+	return p.Row + max(1, p.RowSpan) - 1
+}
+
+func (p Placement) lastColumn() int {
+	return p.Column + max(1, p.ColumnSpan) - 1
+}
+
+// Span returns the rectangle covering all cells of the placement.
+// Spacing between the spanned rows and columns is part of the rectangle.
+//
+// It panics if the placement is out of bounds.
+func (c Cells) Span(p Placement) uv.Rectangle {
+	first := c[p.Row][p.Column]
+	last := c[p.lastRow()][p.lastColumn()]
+
+	return uv.Rectangle{Min: first.Min, Max: last.Max}
+}
+
+// Place returns the rectangles of the given placements, see [Cells.Span].
+//
+// Returns [ErrPlacementOutOfBounds] if a placement does not fit into the grid
+// and [*OverlappingPlacementError] if two placements cover the same cell.
+func (c Cells) Place(placements ...Placement) ([]uv.Rectangle, error) {
+	// owners maps the cells to the indexes of the placements covering them.
+	owners := make(map[[2]int]int)
+	rects := make([]uv.Rectangle, 0, len(placements))
+
+	for i, p := range placements {
+		if p.Row < 0 || p.Column < 0 || p.lastRow() >= len(c) || p.lastColumn() >= len(c[p.Row]) {
+			return nil, fmt.Errorf("placement %d: %w", i, ErrPlacementOutOfBounds)
+		}
+
+		for row := p.Row; row <= p.lastRow(); row++ {
+			for column := p.Column; column <= p.lastColumn(); column++ {
+				if other, ok := owners[[2]int{row, column}]; ok {
+					return nil, &OverlappingPlacementError{
+						Index:  i,
+						Other:  other,
+						Row:    row,
+						Column: column,
+					}
+				}
+
+				owners[[2]int{row, column}] = i
+			}
+		}
+
+		rects = append(rects, c.Span(p))
+	}
+
+	return rects, nil
+}
+
+// Grid splits an area into rows and columns in a single call.
+//
+// Rows and columns are solved once for the whole area,
+// so cells of the same column are always aligned.
+type Grid struct {
+	Rows    []Constraint
+	Columns []Constraint
+
+	RowSpacing    Spacing
+	ColumnSpacing Spacing
+
+	RowFlex    Flex
+	ColumnFlex Flex
+
+	Padding Padding
+
+	// Cache stores split results of rows and columns.
+	// When nil, the global cache is used.
+	Cache *Cache
+}
+
+func NewGrid(rows, columns []Constraint) Grid {
+	return Grid{
+		Rows:          rows,
+		Columns:       columns,
+		RowSpacing:    SpacingSpace(0),
+		ColumnSpacing: SpacingSpace(0),
+		RowFlex:       FlexLegacy,
+		ColumnFlex:    FlexLegacy,
+		Padding:       NewPadding(),
+	}
+}
+
+func (g Grid) WithRows(rows ...Constraint) Grid {
+	g.Rows = rows
+	return g
+}
+
+func (g Grid) WithColumns(columns ...Constraint) Grid {
+	g.Columns = columns
+	return g
+}
+
+func (g Grid) WithRowSpacing(spacing Spacing) Grid {
+	g.RowSpacing = spacing
+	return g
+}
+
+func (g Grid) WithColumnSpacing(spacing Spacing) Grid {
+	g.ColumnSpacing = spacing
+	return g
+}
+
+// WithSpacing sets the same spacing between rows and between columns.
+func (g Grid) WithSpacing(spacing Spacing) Grid {
+	g.RowSpacing = spacing
+	g.ColumnSpacing = spacing
+
+	return g
+}
+
+func (g Grid) WithRowFlex(flex Flex) Grid {
+	g.RowFlex = flex
+	return g
+}
+
+func (g Grid) WithColumnFlex(flex Flex) Grid {
+	g.ColumnFlex = flex
+	return g
+}
+
+// WithFlex sets the same flex for rows and columns.
+func (g Grid) WithFlex(flex Flex) Grid {
+	g.RowFlex = flex
+	g.ColumnFlex = flex
+
+	return g
+}
+
+func (g Grid) WithPadding(padding Padding) Grid {
+	g.Padding = padding
+	return g
+}
+
+func (g Grid) WithCache(cache *Cache) Grid {
+	g.Cache = cache
+	return g
+}
+
+// RowsLayout returns the vertical layout used to split the rows.
+func (g Grid) RowsLayout() Layout {
+	return Vertical(g.Rows...).
+		WithFlex(g.RowFlex).
+		WithSpacing(g.RowSpacing).
+		WithCache(g.Cache)
+}
+
+// ColumnsLayout returns the horizontal layout used to split the columns.
+func (g Grid) ColumnsLayout() Layout {
+	return Horizontal(g.Columns...).
+		WithFlex(g.ColumnFlex).
+		WithSpacing(g.ColumnSpacing).
+		WithCache(g.Cache)
+}
+
+// TrySplit splits the given area into cells.
+//
+// See [Layout.TrySplit] for the returned errors.
+func (g Grid) TrySplit(area uv.Rectangle) (Cells, error) {
+	innerArea := g.Padding.Apply(area)
+
+	rows, _, err := g.RowsLayout().TrySplit(innerArea)
+	if err != nil {
+		return nil, fmt.Errorf("split rows: %w", err)
+	}
+
+	columns, _, err := g.ColumnsLayout().TrySplit(innerArea)
+	if err != nil {
+		return nil, fmt.Errorf("split columns: %w", err)
+	}
+
+	cells := make(Cells, len(rows))
+
+	for r, row := range rows {
+		cells[r] = make([]uv.Rectangle, len(columns))
+
+		for c, column := range columns {
+			cells[r][c] = uv.Rect(column.Min.X, row.Min.Y, column.Dx(), row.Dy())
+		}
+	}
+
+	return cells, nil
+}
+
+// Split splits the given area into cells.
+//
+// It panics if the constraints can not be solved, see [Grid.TrySplit].
+func (g Grid) Split(area uv.Rectangle) Cells {
+	cells, err := g.TrySplit(area)
+	if err != nil {
+		panic(err)
+	}
+
+	return cells
+}
+
+// TryPlace splits the given area and returns the rectangles of the placements.
+//
+// See [Grid.TrySplit] and [Cells.Place] for the returned errors.
+func (g Grid) TryPlace(area uv.Rectangle, placements ...Placement) ([]uv.Rectangle, error) {
+	cells, err := g.TrySplit(area)
+	if err != nil {
+		return nil, err
+	}
+
+	return cells.Place(placements...)
+}
@@ -0,0 +1,110 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrid(t *testing.T) {
+	t.Run("cells", func(t *testing.T) {
+		grid := NewGrid(
+			[]Constraint{Len(1), Fill(1)},
+			[]Constraint{Len(4), Fill(1), Len(4)},
+		).WithColumnSpacing(SpacingSpace(1))
+
+		cells := grid.Split(uv.Rect(0, 0, 20, 5))
+
+		require.Equal(t, Cells{
+			{uv.Rect(0, 0, 4, 1), uv.Rect(5, 0, 10, 1), uv.Rect(16, 0, 4, 1)},
+			{uv.Rect(0, 1, 4, 4), uv.Rect(5, 1, 10, 4), uv.Rect(16, 1, 4, 4)},
+		}, cells)
+
+		require.Equal(t, uv.Rect(5, 1, 10, 4), cells.Cell(1, 1))
+		require.Equal(t, Splitted{uv.Rect(0, 0, 4, 1), uv.Rect(5, 0, 10, 1), uv.Rect(16, 0, 4, 1)}, cells.Row(0))
+		require.Equal(t, Splitted{uv.Rect(16, 0, 4, 1), uv.Rect(16, 1, 4, 4)}, cells.Column(2))
+	})
+
+	t.Run("matches nested layouts", func(t *testing.T) {
+		grid := NewGrid(
+			[]Constraint{Len(2), Percentage(50), Fill(1)},
+			[]Constraint{Len(3), Len(3)},
+		).
+			WithPadding(NewPadding(1, 2)).
+			WithRowSpacing(SpacingSpace(1)).
+			WithColumnFlex(FlexCenter)
+
+		area := uv.Rect(3, 2, 30, 20)
+
+		cells, err := grid.TrySplit(area)
+		require.NoError(t, err)
+
+		rows := grid.RowsLayout().Split(grid.Padding.Apply(area))
+		require.Len(t, cells, len(rows))
+
+		for r, row := range rows {
+			columns := grid.ColumnsLayout().Split(row)
+
+			require.Equal(t, []uv.Rectangle(columns), cells[r])
+		}
+
+		require.Equal(t, uv.Rect(15, 3, 3, 2), cells.Cell(0, 0))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		cells := NewGrid(nil, []Constraint{Fill(1)}).Split(uv.Rect(0, 0, 10, 10))
+
+		require.Empty(t, cells)
+	})
+}
+
+func TestGridPlace(t *testing.T) {
+	grid := NewGrid(
+		[]Constraint{Len(1), Fill(1), Fill(1)},
+		[]Constraint{Fill(1), Fill(1), Fill(1)},
+	).WithSpacing(SpacingSpace(1))
+
+	area := uv.Rect(0, 0, 32, 11)
+
+	t.Run("spans absorb spacing", func(t *testing.T) {
+		rects, err := grid.TryPlace(
+			area,
+			Place(0, 0).WithColumnSpan(3),
+			Place(1, 0).WithSpan(2, 2),
+			Place(1, 2),
+			Place(2, 2),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, []uv.Rectangle{
+			uv.Rect(0, 0, 32, 1),
+			uv.Rect(0, 2, 21, 9),
+			uv.Rect(22, 2, 10, 4),
+			uv.Rect(22, 7, 10, 4),
+		}, rects)
+	})
+
+	t.Run("zero span is a single cell", func(t *testing.T) {
+		cells := grid.Split(area)
+
+		require.Equal(t, cells.Cell(1, 1), cells.Span(Placement{Row: 1, Column: 1}))
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		_, err := grid.TryPlace(area, Place(1, 0).WithSpan(2, 2), Place(0, 1).WithRowSpan(3))
+
+		var target *OverlappingPlacementError
+
+		require.ErrorAs(t, err, &target)
+		require.Equal(t, OverlappingPlacementError{Index: 1, Other: 0, Row: 1, Column: 1}, *target)
+	})
+
+	t.Run("out of bounds", func(t *testing.T) {
+		_, err := grid.TryPlace(area, Place(0, 0), Place(2, 2).WithColumnSpan(2))
+		require.ErrorIs(t, err, ErrPlacementOutOfBounds)
+
+		_, err = grid.TryPlace(area, Place(-1, 0))
+		require.ErrorIs(t, err, ErrPlacementOutOfBounds)
+	})
+}
@@ -3,9 +3,11 @@ package uvcasso
 import (
 	"fmt"
 	"math"
+	"slices"
+	"strconv"
 
 	uv "github.com/charmbracelet/ultraviolet"
-	"github.com/metafates/uvcasso/internal/casso"
+	"github.com/metafates/uvcasso/casso"
 )
 
 const _floatPrecisionMultiplier float64 = 100.0
@@ -16,6 +18,7 @@ const (
 	_maxSizeLTE   casso.Strength = casso.Strong * 100.0
 	// _minSizeLTE       casso.Strength = casso.Strong * 100.0
 	_lengthSizeEq     casso.Strength = casso.Strong * 10.0
+	_autoSizeEq       casso.Strength = casso.Strong * 5.0
 	_percentageSizeEq casso.Strength = casso.Strong
 	_ratioSizeEq      casso.Strength = casso.Strong / 10.0
 	_minSizeEq        casso.Strength = casso.Medium * 10.0
@@ -67,6 +70,19 @@ type Layout struct {
 	Padding     Padding
 	Spacing     Spacing
 	Flex        Flex
+
+	// Gaps size the spacers between the segments, Gaps[i] is the gap
+	// between the segments i and i+1. Gaps which are nil or missing
+	// use Spacing. A gap is sized by [Len], [Min], [Max], [Percentage],
+	// [Ratio], [Fill] or [Calc], e.g. [Len] for a fixed gap or [Fill] for
+	// a gap taking the excess space, other constraints are rejected with
+	// [*GapError]. Around a [Hidden] segment the gap before the next
+	// visible segment is used.
+	Gaps []Constraint
+
+	// Cache stores split results of this layout.
+	// When nil, the global cache is used.
+	Cache *Cache
 }
 
 func (l Layout) WithDirection(direction Direction) Layout {
@@ -90,13 +106,127 @@ func (l Layout) WithSpacing(spacing Spacing) Layout {
 	return l
 }
 
+// WithGaps sets the gaps between the segments, see [Layout.Gaps].
+func (l Layout) WithGaps(gaps ...Constraint) Layout {
+	l.Gaps = gaps
+	return l
+}
+
+// gapBefore returns the gap constraint before the segment at the given index or nil.
+func (l Layout) gapBefore(i int) Constraint {
+	if i < 1 || i > len(l.Gaps) {
+		return nil
+	}
+
+	return l.Gaps[i-1]
+}
+
+func (l Layout) WithCache(cache *Cache) Layout {
+	l.Cache = cache
+	return l
+}
+
+func (l Layout) WithoutCache() Layout {
+	l.Cache = NewCache(0)
+	return l
+}
+
 func (l Layout) WithConstraints(constraints ...Constraint) Layout {
 	l.Constraints = append(l.Constraints, constraints...)
 	return l
 }
 
+// TrySplit splits the given area into segments and spacers.
+//
+// Unlike [Layout.Split] and [Layout.SplitWithSpacers] it does not panic
+// when the constraints can not be solved. The returned error is either
+// [*UnsatisfiableConstraintError], [*InternalSolverError], [*GapError]
+// or wraps [ErrUnknownSegment] for a [Relative] with an unknown Index.
+func (l Layout) TrySplit(area uv.Rectangle) (segments, spacers Splitted, err error) {
+	segments, spacers, _, err = l.TrySplitHidden(area)
+	return segments, spacers, err
+}
+
+// TrySplitHidden is like [Layout.TrySplit] but also returns the indexes
+// of the segments hidden by [Hidden] or [Prioritized], in ascending order.
+func (l Layout) TrySplitHidden(area uv.Rectangle) (segments, spacers Splitted, hidden []int, err error) {
+	cache := l.Cache
+	if cache == nil {
+		cache = _globalCache
+	}
+
+	measured := l.measure(area)
+	key := measured.cacheKey(area)
+
+	// Segments are aligned after caching, an [Auto] or [Measured] size
+	// of [Aligned] is not part of the key.
+	if segments, spacers, hidden, ok := cache.get(key); ok {
+		measured.alignSegments(segments)
+
+		return segments, spacers, hidden, nil
+	}
+
+	segments, spacers, err = measured.split(area)
+	if err != nil {
+		return nil, nil, nil, wrapSolverError(err)
+	}
+
+	hidden = hiddenIndexes(measured.Constraints)
+
+	cache.put(key, segments, spacers, hidden)
+
+	measured.alignSegments(segments)
+
+	return segments, spacers, hidden, nil
+}
+
+// measure returns the layout with [Auto] and [Measured] constraints
+// replaced by their measured sizes for the given area
+// and [Prioritized] constraints hidden if they do not fit.
+func (l Layout) measure(area uv.Rectangle) Layout {
+	innerArea := l.Padding.Apply(area)
+
+	main, cross := innerArea.Dy(), innerArea.Dx()
+	if l.Direction == DirectionHorizontal {
+		main, cross = innerArea.Dx(), innerArea.Dy()
+	}
+
+	return l.measureCross(cross).prioritize(main)
+}
+
+// measureCross is like [Layout.measure] but takes the cross size of the inner area.
+func (l Layout) measureCross(cross int) Layout {
+	var constraints []Constraint
+
+	for i, c := range l.Constraints {
+		var resolved Constraint
+
+		switch base := baseConstraint(c).(type) {
+		case Auto:
+			resolved = _AutoLen(measureConstraint(base, l.Direction, cross).Preferred)
+		case Measured:
+			resolved = _MeasuredLen(measure(base, l.Direction, cross))
+		default:
+			continue
+		}
+
+		// Copy on write, the constraints may be shared with the caller.
+		if constraints == nil {
+			constraints = slices.Clone(l.Constraints)
+		}
+
+		constraints[i] = replaceBase(c, resolved)
+	}
+
+	if constraints != nil {
+		l.Constraints = constraints
+	}
+
+	return l
+}
+
 func (l Layout) SplitWithSpacers(area uv.Rectangle) (segments, spacers Splitted) {
-	segments, spacers, err := l.split(area)
+	segments, spacers, err := l.TrySplit(area)
 	if err != nil {
 		panic(err)
 	}
@@ -110,6 +240,7 @@ func (l Layout) Split(area uv.Rectangle) Splitted {
 	return segments
 }
 
+// split solves the layout for the given area.
 func (l Layout) split(area uv.Rectangle) (segments, spacers []uv.Rectangle, err error) {
 	solver := casso.NewSolver()
 
@@ -127,81 +258,213 @@ func (l Layout) split(area uv.Rectangle) (segments, spacers []uv.Rectangle, err
 		areaEnd = float64(innerArea.Max.Y) * _floatPrecisionMultiplier
 	}
 
-	variableCount := len(l.Constraints)*2 + 2
-
-	variables := make([]casso.Variable, variableCount)
-	for i := range variableCount {
-		variables[i] = casso.NewVariable()
-	}
+	variables := newVariables(solver, "", len(l.Constraints))
 
 	spacerElements := newElements(variables)
 	segmentElements := newElements(variables[1:])
 
-	var spacing int
+	areaSize := _Element{
+		Start: variables[0],
+		End:   variables[len(variables)-1],
+	}
+
+	if err := configureArea(solver, areaSize, areaStart, areaEnd); err != nil {
+		return nil, nil, fmt.Errorf("configure area: %w", err)
+	}
+
+	if err := configureLayout(solver, l, variables); err != nil {
+		return nil, nil, err
+	}
+
+	changes := make([]float64, solver.VariableCount())
+	for _, c := range solver.FetchChanges() {
+		changes[c.Variable] = c.Constant
+	}
+
+	segments = changesToRects(changes, segmentElements, innerArea, l.Direction)
+	spacers = changesToRects(changes, spacerElements, innerArea, l.Direction)
+
+	return segments, spacers, nil
+}
 
-	switch s := l.Spacing.(type) {
-	case SpacingSpace:
-		spacing = int(s)
+// configureLayout adds the constraints splitting the area between the segments
+// of the layout. The variables are allocated by [newVariables], the area is
+// bounded by the first and the last one.
+func configureLayout(
+	solver *casso.Solver,
+	l Layout,
+	variables []casso.Variable,
+) error {
+	for i, gap := range l.Gaps {
+		if gap != nil && !isGapConstraint(gap) {
+			return &GapError{Index: i, Constraint: gap}
+		}
+	}
 
-	case SpacingOverlap:
-		spacing = -int(s)
+	for i, c := range l.Constraints {
+		if r, ok := baseConstraint(c).(Relative); ok && (r.Index < 0 || r.Index >= len(l.Constraints)) {
+			return fmt.Errorf("relative constraint %d: %w: %d", i, ErrUnknownSegment, r.Index)
+		}
 	}
 
+	spacerElements := newElements(variables)
+	segmentElements := newElements(variables[1:])
+
+	spacing := spacingSize(l.Spacing)
+
 	areaSize := _Element{
 		Start: variables[0],
 		End:   variables[len(variables)-1],
 	}
 
-	if err := configureArea(&solver, areaSize, areaStart, areaEnd); err != nil {
-		return nil, nil, fmt.Errorf("configure area: %w", err)
+	if err := configureVariableInAreaConstraints(solver, variables, areaSize); err != nil {
+		return fmt.Errorf("configure variable in area constraints: %w", err)
 	}
 
-	if err := configureVariableInAreaConstraints(&solver, variables, areaSize); err != nil {
-		return nil, nil, fmt.Errorf("configure variable in area constraints: %w", err)
+	if err := configureVariableConstraints(solver, variables); err != nil {
+		return fmt.Errorf("configure variable constraints: %w", err)
 	}
 
-	if err := configureVariableConstraints(&solver, variables); err != nil {
-		return nil, nil, fmt.Errorf("configure variable constraints: %w", err)
+	visibleSpacers, visibleSegments, err := configureHidden(solver, spacerElements, segmentElements, l.Constraints)
+	if err != nil {
+		return fmt.Errorf("configure hidden constraints: %w", err)
 	}
 
-	if err := configureFlexConstraints(&solver, areaSize, spacerElements, l.Flex, spacing); err != nil {
-		return nil, nil, fmt.Errorf("configure flex constraints: %w", err)
+	flexSpacers, gapSpacers, gaps := splitGaps(spacerElements, visibleSpacers, l)
+
+	if err := configureFlexConstraints(solver, areaSize, flexSpacers, l.Flex, spacing); err != nil {
+		return fmt.Errorf("configure flex constraints: %w", err)
 	}
 
-	if err := configureConstraints(&solver, areaSize, segmentElements, l.Constraints, l.Flex); err != nil {
-		return nil, nil, fmt.Errorf("configure constraints: %w", err)
+	if err := configureConstraints(solver, areaSize, gapSpacers, gaps, l.Flex); err != nil {
+		return fmt.Errorf("configure gap constraints: %w", err)
 	}
 
-	if err := configureFillConstraints(&solver, segmentElements, l.Constraints, l.Flex); err != nil {
-		return nil, nil, fmt.Errorf("configure fill constraints: %w", err)
+	if err := configureFillConstraints(solver, gapSpacers, gaps, l.Flex); err != nil {
+		return fmt.Errorf("configure gap fill constraints: %w", err)
+	}
+
+	if err := configureConstraints(solver, areaSize, segmentElements, l.Constraints, l.Flex); err != nil {
+		return fmt.Errorf("configure constraints: %w", err)
+	}
+
+	if err := configureFillConstraints(solver, segmentElements, l.Constraints, l.Flex); err != nil {
+		return fmt.Errorf("configure fill constraints: %w", err)
 	}
 
 	if l.Flex != FlexLegacy {
-		for i := 0; i < len(segmentElements)-1; i++ {
-			left := segmentElements[i]
-			right := segmentElements[i+1]
+		for i := 0; i < len(visibleSegments)-1; i++ {
+			left := visibleSegments[i]
+			right := visibleSegments[i+1]
 
 			if err := solver.AddConstraint(left.hasSize(right.size(), _allSegmentGrow)); err != nil {
-				return nil, nil, fmt.Errorf("add has size constraint: %w", err)
+				return fmt.Errorf("add has size constraint: %w", err)
 			}
 		}
 	}
 
-	fetched := solver.FetchChanges()
+	return nil
+}
 
-	changes := make(map[casso.Variable]float64, len(fetched))
-	for _, c := range fetched {
-		changes[c.Variable] = c.Constant
+// configureHidden collapses the hidden segments and the spacers around them.
+//
+// Between two visible segments only the spacer right before the second one
+// is kept, the first and the last spacers are always kept. The kept spacers
+// and the visible segments are returned.
+func configureHidden(
+	solver *casso.Solver,
+	spacers []_Element,
+	segments []_Element,
+	constraints []Constraint,
+) (visibleSpacers, visibleSegments []_Element, err error) {
+	if !slices.ContainsFunc(constraints, isHidden) {
+		return spacers, segments, nil
 	}
 
-	segments = changesToRects(changes, segmentElements, innerArea, l.Direction)
-	spacers = changesToRects(changes, spacerElements, innerArea, l.Direction)
+	keep := make([]bool, len(spacers))
+	keep[0], keep[len(keep)-1] = true, true
 
-	return segments, spacers, nil
+	var visible int
+
+	for i, s := range segments {
+		if i < len(constraints) && isHidden(constraints[i]) {
+			if err := solver.AddConstraint(s.isEmpty()); err != nil {
+				return nil, nil, fmt.Errorf("add is empty constraint: %w", err)
+			}
+
+			continue
+		}
+
+		if visible > 0 {
+			keep[i] = true
+		}
+
+		visible++
+
+		visibleSegments = append(visibleSegments, s)
+	}
+
+	for i, s := range spacers {
+		if keep[i] {
+			visibleSpacers = append(visibleSpacers, s)
+			continue
+		}
+
+		if err := solver.AddConstraint(s.isEmpty()); err != nil {
+			return nil, nil, fmt.Errorf("add is empty constraint: %w", err)
+		}
+	}
+
+	return visibleSpacers, visibleSegments, nil
+}
+
+// splitGaps splits the visible spacers into the ones sized by the flex
+// and the spacing and the inner ones sized by the gaps of the layout.
+func splitGaps(
+	spacers []_Element,
+	visibleSpacers []_Element,
+	l Layout,
+) (flexSpacers, gapSpacers []_Element, gaps []Constraint) {
+	if len(l.Gaps) == 0 {
+		return visibleSpacers, nil, nil
+	}
+
+	// The spacer at index i is the one before the segment at index i.
+	gapOf := make(map[_Element]Constraint)
+
+	for i := 1; i < len(spacers)-1; i++ {
+		if gap := l.gapBefore(i); gap != nil {
+			gapOf[spacers[i]] = gap
+		}
+	}
+
+	for i, s := range visibleSpacers {
+		gap, ok := gapOf[s]
+		if !ok || i == 0 || i == len(visibleSpacers)-1 {
+			flexSpacers = append(flexSpacers, s)
+			continue
+		}
+
+		gapSpacers = append(gapSpacers, s)
+		gaps = append(gaps, gap)
+	}
+
+	return flexSpacers, gapSpacers, gaps
+}
+
+// isGapConstraint reports whether the constraint can size a gap.
+// Other constraints depend on the segments, e.g. [Auto] or [Relative].
+func isGapConstraint(c Constraint) bool {
+	switch c.(type) {
+	case Min, Max, Len, Percentage, Ratio, Fill, Calc:
+		return true
+	default:
+		return false
+	}
 }
 
 func changesToRects(
-	changes map[casso.Variable]float64,
+	changes []float64,
 	elements []_Element,
 	area uv.Rectangle,
 	direction Direction,
@@ -212,8 +475,8 @@ func changesToRects(
 		start := changes[e.Start]
 		end := changes[e.End]
 
-		startRounded := int(math.Round(math.Round(start) / _floatPrecisionMultiplier))
-		endRounded := int(math.Round(math.Round(end) / _floatPrecisionMultiplier))
+		startRounded := fromSolverValue(start)
+		endRounded := fromSolverValue(end)
 
 		size := max(0, endRounded-startRounded)
 
@@ -233,6 +496,11 @@ func changesToRects(
 	return rects
 }
 
+// fromSolverValue rounds a solved value to cells.
+func fromSolverValue(value float64) int {
+	return int(math.Round(math.Round(value) / _floatPrecisionMultiplier))
+}
+
 func configureFillConstraints(
 	solver *casso.Solver,
 	segments []_Element,
@@ -245,7 +513,7 @@ func configureFillConstraints(
 	)
 
 	for i := 0; i < min(len(constraints), len(segments)); i++ {
-		c := constraints[i]
+		c := baseConstraint(constraints[i])
 		s := segments[i]
 
 		switch c.(type) {
@@ -296,6 +564,7 @@ func configureFillConstraints(
 		rhs := rightSegment.size().MulConstant(leftScalingFactor)
 
 		constraint := casso.Equal(_grow).ExpressionLHS(lhs).ExpressionRHS(rhs)
+
 		if err := solver.AddConstraint(constraint); err != nil {
 			return fmt.Errorf("add constraint: %w", err)
 		}
@@ -315,7 +584,7 @@ func configureConstraints(
 		constraint := constraints[i]
 		segment := segments[i]
 
-		switch constraint := constraint.(type) {
+		switch constraint := baseConstraint(constraint).(type) {
 		case Max:
 			size := int(constraint)
 
@@ -330,16 +599,16 @@ func configureConstraints(
 		case Min:
 			size := int(constraint)
 
-			if err := solver.AddConstraint(segment.hasMinSize(size, _minSizeGTE)); err != nil {
+			if err := solver.AddConstraints(segment.hasMinSize(size, _minSizeGTE)); err != nil {
 				return fmt.Errorf("add has min size constraint: %w", err)
 			}
 
 			if flex == FlexLegacy {
-				if err := solver.AddConstraint(segment.hasIntSize(size, _minSizeEq)); err != nil {
+				if err := solver.AddConstraints(segment.hasIntSize(size, _minSizeEq)); err != nil {
 					return fmt.Errorf("add has size constraint: %w", err)
 				}
 			} else {
-				if err := solver.AddConstraint(segment.hasSize(area.size(), _fillGrow)); err != nil {
+				if err := solver.AddConstraints(segment.hasSize(area.size(), _fillGrow)); err != nil {
 					return fmt.Errorf("add has size constraint: %w", err)
 				}
 			}
@@ -347,34 +616,144 @@ func configureConstraints(
 		case Len:
 			length := int(constraint)
 
-			if err := solver.AddConstraint(segment.hasIntSize(length, _lengthSizeEq)); err != nil {
+			if err := solver.AddConstraints(segment.hasIntSize(length, _lengthSizeEq)); err != nil {
+				return fmt.Errorf("add has int size constraint: %w", err)
+			}
+
+		case _AutoLen:
+			length := int(constraint)
+
+			if err := solver.AddConstraints(segment.hasIntSize(length, _autoSizeEq)); err != nil {
 				return fmt.Errorf("add has int size constraint: %w", err)
 			}
 
+		case _MeasuredLen:
+			err := solver.AddConstraints(
+				segment.hasMinSize(constraint.Min, _minSizeGTE),
+				segment.hasIntSize(constraint.Preferred, _autoSizeEq),
+			)
+			if err != nil {
+				return fmt.Errorf("add constraints: %w", err)
+			}
+
 		case Percentage:
 			size := area.size().MulConstant(float64(constraint)).DivConstant(100)
 
-			if err := solver.AddConstraint(segment.hasSize(size, _percentageSizeEq)); err != nil {
+			if err := solver.AddConstraints(segment.hasSize(size, _percentageSizeEq)); err != nil {
 				return fmt.Errorf("add has size constraint: %w", err)
 			}
 
 		case Ratio:
 			size := area.size().MulConstant(float64(constraint.Num)).DivConstant(float64(max(1, constraint.Den)))
 
-			if err := solver.AddConstraint(segment.hasSize(size, _ratioSizeEq)); err != nil {
+			if err := solver.AddConstraints(segment.hasSize(size, _ratioSizeEq)); err != nil {
 				return fmt.Errorf("add has size constraint: %w", err)
 			}
 
 		case Fill:
-			if err := solver.AddConstraint(segment.hasSize(area.size(), _fillGrow)); err != nil {
+			if err := solver.AddConstraints(segment.hasSize(area.size(), _fillGrow)); err != nil {
+				return fmt.Errorf("add has size constraint: %w", err)
+			}
+
+		case Calc:
+			size := area.size().
+				MulConstant(float64(constraint.Num)).
+				DivConstant(float64(max(1, constraint.Den))).
+				AddConstant(float64(constraint.Offset) * _floatPrecisionMultiplier)
+
+			if err := solver.AddConstraints(segment.hasSize(size, _percentageSizeEq)); err != nil {
+				return fmt.Errorf("add has size constraint: %w", err)
+			}
+
+			if constraint.Min != 0 {
+				if err := solver.AddConstraints(segment.hasMinSize(constraint.Min, _minSizeGTE)); err != nil {
+					return fmt.Errorf("add has min size constraint: %w", err)
+				}
+			}
+
+			if constraint.Max != 0 {
+				if err := solver.AddConstraints(segment.hasMaxSize(constraint.Max, _maxSizeLTE)); err != nil {
+					return fmt.Errorf("add has max size constraint: %w", err)
+				}
+			}
+
+		case Relative:
+			scale := constraint.Scale
+			if scale == 0 {
+				scale = 1
+			}
+
+			size := segments[constraint.Index].size().MulConstant(scale)
+
+			if err := solver.AddConstraint(segment.hasSize(size, relationStrength(constraint.Strength))); err != nil {
 				return fmt.Errorf("add has size constraint: %w", err)
 			}
 		}
 	}
 
+	if err := configureGroups(solver, segments, constraints); err != nil {
+		return fmt.Errorf("configure groups: %w", err)
+	}
+
 	return nil
 }
 
+// configureGroups makes the segments of each [Grouped] group share one size.
+func configureGroups(
+	solver *casso.Solver,
+	segments []_Element,
+	constraints []Constraint,
+) error {
+	// first maps the groups to the index of their first segment.
+	first := make(map[string]int)
+
+	for i := 0; i < min(len(constraints), len(segments)); i++ {
+		// Hidden segments are empty, they would shrink the group to nothing.
+		grouped, ok := findGrouped(constraints[i])
+		if !ok || isHidden(constraints[i]) {
+			continue
+		}
+
+		j, ok := first[grouped.Group]
+		if !ok {
+			first[grouped.Group] = i
+			continue
+		}
+
+		constraint := segments[i].hasSize(segments[j].size(), relationStrength(grouped.Strength))
+
+		if err := solver.AddConstraint(constraint); err != nil {
+			return fmt.Errorf("add has size constraint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// findGrouped returns the outermost [Grouped] wrapper of the constraint.
+func findGrouped(c Constraint) (Grouped, bool) {
+	for {
+		switch w := c.(type) {
+		case Grouped:
+			return w, true
+		case _wrapper:
+			c = w.unwrap()
+		default:
+			return Grouped{}, false
+		}
+	}
+}
+
+// relationStrength returns the strength of a relation between segments,
+// defaulting to [_relationEq].
+func relationStrength(strength casso.Strength) casso.Strength {
+	if strength == 0 {
+		return _relationEq
+	}
+
+	return strength
+}
+
 func configureFlexConstraints(
 	solver *casso.Solver,
 	area _Element,
@@ -464,6 +843,29 @@ func configureFlexConstraints(
 
 		}
 
+	case FlexSpaceEvenly:
+		if len(spacers) >= 2 {
+			for _, indices := range combinations(len(spacers), 2) {
+				i, j := indices[0], indices[1]
+
+				left, right := spacers[i], spacers[j]
+
+				if err := solver.AddConstraint(left.hasSize(right.size(), _spacerSizeEq)); err != nil {
+					return fmt.Errorf("add has size constraint: %w", err)
+				}
+			}
+		}
+
+		for _, s := range spacers {
+			err := solver.AddConstraints(
+				s.hasMinSize(spacing, _spacerSizeEq),
+				s.hasSize(area.size(), _spaceGrow),
+			)
+			if err != nil {
+				return fmt.Errorf("add constraints: %w", err)
+			}
+		}
+
 	case FlexStart:
 		for _, s := range spacersExceptFirstAndLast {
 			if err := solver.AddConstraint(s.hasSize(casso.NewExpressionFromConstant(spacingF), _spacerSizeEq)); err != nil {
@@ -591,6 +993,39 @@ func configureArea(
 	return nil
 }
 
+// newVariables allocates variables for the area bounds and the
+// boundaries between the given number of segments and their spacers.
+//
+// Variable names are prefixed with the given prefix. They are only
+// formatted when needed, e.g. to describe a conflict.
+func newVariables(solver *casso.Solver, prefix string, segments int) []casso.Variable {
+	count := segments*2 + 2
+
+	first := solver.NewVariables(count, func(i int) string {
+		switch i {
+		case 0:
+			return prefix + "area.start"
+		case count - 1:
+			return prefix + "area.end"
+		}
+
+		index := strconv.Itoa((i - 1) / 2)
+
+		if i%2 == 1 {
+			return prefix + "segment[" + index + "].start"
+		}
+
+		return prefix + "segment[" + index + "].end"
+	})
+
+	variables := make([]casso.Variable, count)
+	for i := range variables {
+		variables[i] = first + casso.Variable(i)
+	}
+
+	return variables
+}
+
 func newElements(variables []casso.Variable) []_Element {
 	count := len(variables)
 
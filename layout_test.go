@@ -1,24 +1,28 @@
 package uvcasso
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/ultraviolet/screen"
+	"github.com/metafates/uvcasso/casso"
 	"github.com/stretchr/testify/require"
 )
 
 type LayoutSplitTestCase struct {
 	Name        string
 	Flex        Flex
+	Spacing     Spacing
+	Gaps        []Constraint
 	Width       int
 	Constraints []Constraint
 	Want        string
 }
 
 func (tc LayoutSplitTestCase) Test(t *testing.T) {
-	letters(t, tc.Flex, tc.Constraints, tc.Width, tc.Want)
+	letters(t, tc.Flex, tc.Spacing, tc.Gaps, tc.Constraints, tc.Width, tc.Want)
 }
 
 func TestLength(t *testing.T) {
@@ -685,6 +689,14 @@ func TestMin(t *testing.T) {
 			Constraints: []Constraint{Min(2), Min(2)},
 			Want:        "aab",
 		},
+		{
+			Name:        "fills equal around min",
+			Flex:        FlexSpaceBetween,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Fill(1), Min(0), Fill(0), Fill(1)},
+			Want:        "aa bbb  dd",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1087,6 +1099,794 @@ func TestPercentageFlexSpaceBetween(t *testing.T) {
 	}
 }
 
+func TestPercentageFlexSpaceEvenly(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "Flex SpaceEvenly with Percentage 0, 0",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(0), Percentage(0)},
+			Want:        "          ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 0, 25",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(0), Percentage(25)},
+			Want:        "     bbb  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 0, 50",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(0), Percentage(50)},
+			Want:        "   bbbbb  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 0, 100",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(0), Percentage(100)},
+			Want:        "bbbbbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 0, 200",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(0), Percentage(200)},
+			Want:        "bbbbbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 10, 0",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(10), Percentage(0)},
+			Want:        "   a      ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 10, 25",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(10), Percentage(25)},
+			Want:        "  a  bbb  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 10, 50",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(10), Percentage(50)},
+			Want:        " a  bbbbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 10, 100",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(10), Percentage(100)},
+			Want:        "abbbbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 10, 200",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(10), Percentage(200)},
+			Want:        "abbbbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 25, 0",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(25), Percentage(0)},
+			Want:        "   aa     ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 25, 25",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(25), Percentage(25)},
+			Want:        "  aa  bb  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 25, 50",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(25), Percentage(50)},
+			Want:        " aa bbbbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 25, 100",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(25), Percentage(100)},
+			Want:        "aaabbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 25, 200",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(25), Percentage(200)},
+			Want:        "aaabbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 33, 0",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(33), Percentage(0)},
+			Want:        "  aaaa    ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 33, 25",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(33), Percentage(25)},
+			Want:        " aaaa bbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 33, 50",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(33), Percentage(50)},
+			Want:        " aaabbbbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 33, 100",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(33), Percentage(100)},
+			Want:        "aaabbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 33, 200",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(33), Percentage(200)},
+			Want:        "aaabbbbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 50, 0",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(50), Percentage(0)},
+			Want:        "  aaaaa   ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 50, 25",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(50), Percentage(25)},
+			Want:        " aaaaa bb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 50, 50",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(50), Percentage(50)},
+			Want:        "aaaaabbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 50, 100",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(50), Percentage(100)},
+			Want:        "aaaaabbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 50, 200",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(50), Percentage(200)},
+			Want:        "aaaaabbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 100, 0",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(100), Percentage(0)},
+			Want:        "aaaaaaaaaa",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 100, 25",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(100), Percentage(25)},
+			Want:        "aaaaaaaabb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 100, 50",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(100), Percentage(50)},
+			Want:        "aaaaabbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 100, 100",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(100), Percentage(100)},
+			Want:        "aaaaabbbbb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Percentage 100, 200",
+			Flex:        FlexSpaceEvenly,
+			Width:       10,
+			Constraints: []Constraint{Percentage(100), Percentage(200)},
+			Want:        "aaaaabbbbb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+}
+
+func TestFlexSpaceEvenly(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "Flex SpaceEvenly with Len(2), Len(2) and spacing 0",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(0),
+			Width:       10,
+			Constraints: []Constraint{Len(2), Len(2)},
+			Want:        "  aa  bb  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Len(1), Len(1), Len(1) and spacing 0",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(0),
+			Width:       10,
+			Constraints: []Constraint{Len(1), Len(1), Len(1)},
+			Want:        "  a  b c  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Len(4), Len(4) and spacing 0",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(0),
+			Width:       10,
+			Constraints: []Constraint{Len(4), Len(4)},
+			Want:        " aaaabbbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Fill(1), Len(2) and spacing 0",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(0),
+			Width:       10,
+			Constraints: []Constraint{Fill(1), Len(2)},
+			Want:        "aaaaaaaabb",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Len(2), Len(2) and spacing 1",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Len(2), Len(2)},
+			Want:        "  aa  bb  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Len(4), Len(4) and spacing 1",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Len(4), Len(4)},
+			Want:        " aaaa bbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Fill(1), Len(2) and spacing 1",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Fill(1), Len(2)},
+			Want:        " aaaaa bb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Max(2), Min(2) and spacing 1",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Max(2), Min(2)},
+			Want:        " aa bbbbb ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Len(1), Len(1), Len(1) and spacing 2",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(2),
+			Width:       10,
+			Constraints: []Constraint{Len(1), Len(1), Len(1)},
+			Want:        "  a    c  ",
+		},
+		{
+			Name:        "Flex SpaceEvenly with Fill(1), Len(2) and spacing 2",
+			Flex:        FlexSpaceEvenly,
+			Spacing:     SpacingSpace(2),
+			Width:       10,
+			Constraints: []Constraint{Fill(1), Len(2)},
+			Want:        "  aa  bb  ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+}
+
+func TestAuto(t *testing.T) {
+	fixed := func(size int) Auto {
+		return func(int) int { return size }
+	}
+
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "Auto, Fill",
+			Width:       10,
+			Constraints: []Constraint{fixed(5), Fill(1)},
+			Want:        "aaaaabbbbb",
+		},
+		{
+			Name:        "Len wins over Auto",
+			Width:       10,
+			Constraints: []Constraint{fixed(8), Len(8)},
+			Want:        "aabbbbbbbb",
+		},
+		{
+			Name:        "Auto wins over Percentage",
+			Width:       10,
+			Constraints: []Constraint{Percentage(50), fixed(6)},
+			Want:        "aaaabbbbbb",
+		},
+		{
+			Name:        "Min wins over Auto",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{fixed(8), Min(4)},
+			Want:        "aaaaaabbbb",
+		},
+		{
+			Name:        "Auto, Fill, Auto",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{fixed(6), Fill(1), fixed(2)},
+			Want:        "aaaaaabbcc",
+		},
+		{
+			Name:        "centered Auto",
+			Flex:        FlexCenter,
+			Width:       10,
+			Constraints: []Constraint{fixed(3), fixed(3)},
+			Want:        "  aaabbb  ",
+		},
+		{
+			Name:        "nil Auto",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{Auto(nil), Len(2)},
+			Want:        "bb        ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+
+	t.Run("measures with cross size", func(t *testing.T) {
+		var got []int
+
+		// Height of a 30 cells long text wrapped to the given width.
+		text := Auto(func(width int) int {
+			got = append(got, width)
+
+			return (30 + width - 1) / width
+		})
+
+		layout := Vertical(text, Fill(1)).WithPadding(NewPadding(0, 1)).WithoutCache()
+
+		require.Equal(t, Splitted{uv.Rect(1, 0, 10, 3), uv.Rect(1, 3, 10, 7)}, layout.Split(uv.Rect(0, 0, 12, 10)))
+		require.Equal(t, []int{10}, got)
+	})
+
+	t.Run("cached by measured size", func(t *testing.T) {
+		size := 2
+
+		layout := Horizontal(Auto(func(int) int { return size }), Fill(1)).WithCache(NewCache(10))
+		area := uv.Rect(0, 0, 10, 1)
+
+		require.Equal(t, uv.Rect(0, 0, 2, 1), layout.Split(area)[0])
+
+		size = 4
+
+		require.Equal(t, uv.Rect(0, 0, 4, 1), layout.Split(area)[0])
+	})
+}
+
+func TestCalc(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "half minus 2",
+			Flex:        FlexStart,
+			Width:       20,
+			Constraints: []Constraint{Calc{Num: 1, Den: 2, Offset: -2}},
+			Want:        "aaaaaaaa            ",
+		},
+		{
+			Name:        "third plus 1",
+			Flex:        FlexStart,
+			Width:       20,
+			Constraints: []Constraint{Calc{Num: 1, Den: 3, Offset: 1}, Fill(1)},
+			Want:        "aaaaaaaabbbbbbbbbbbb",
+		},
+		{
+			Name:        "clamped to min",
+			Flex:        FlexStart,
+			Width:       20,
+			Constraints: []Constraint{Calc{Num: 1, Den: 2, Offset: -2, Min: 9}},
+			Want:        "aaaaaaaaa           ",
+		},
+		{
+			Name:        "clamped to max",
+			Flex:        FlexStart,
+			Width:       20,
+			Constraints: []Constraint{Calc{Num: 1, Den: 1, Max: 5}},
+			Want:        "aaaaa               ",
+		},
+		{
+			Name:        "constant",
+			Flex:        FlexStart,
+			Width:       20,
+			Constraints: []Constraint{Calc{Offset: 3}},
+			Want:        "aaa                 ",
+		},
+		{
+			Name:        "Len wins",
+			Flex:        FlexStart,
+			Width:       20,
+			Constraints: []Constraint{Calc{Num: 1, Den: 2, Offset: 2}, Len(12)},
+			Want:        "aaaaaaaabbbbbbbbbbbb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "Calc(1/2 - 2)", Calc{Num: 1, Den: 2, Offset: -2}.String())
+		require.Equal(t, "Calc(1/3 + 1, Min(2), Max(10))", Calc{Num: 1, Den: 3, Offset: 1, Min: 2, Max: 10}.String())
+	})
+}
+
+func TestRelative(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "twice the first segment",
+			Width:       10,
+			Constraints: []Constraint{Len(2), Fill(1), Relative{Index: 0, Scale: 2}},
+			Want:        "aabbbbcccc",
+		},
+		{
+			Name:        "flex start",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{Len(2), Relative{Index: 0, Scale: 2}},
+			Want:        "aabbbb    ",
+		},
+		{
+			Name:        "wins over Len",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{Len(2), Len(2), Relative{Index: 0, Scale: 3}},
+			Want:        "aabbcccccc",
+		},
+		{
+			Name:        "weak gives way",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{Len(2), Len(2), Relative{Index: 0, Scale: 3, Strength: casso.Weak}},
+			Want:        "aabb      ",
+		},
+		{
+			Name:        "zero scale",
+			Flex:        FlexStart,
+			Width:       10,
+			Constraints: []Constraint{Min(1), Relative{Index: 0}},
+			Want:        "aaaaabbbbb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+
+	t.Run("unknown index", func(t *testing.T) {
+		for _, layout := range []Layout{
+			Horizontal(Len(2), Relative{Index: 2}),
+			Horizontal(Len(2), Relative{Index: -1}),
+		} {
+			_, _, err := layout.TrySplit(uv.Rect(0, 0, 10, 1))
+
+			require.ErrorIs(t, err, ErrUnknownSegment)
+		}
+	})
+
+	// Relations have no constant, so even required ones
+	// are satisfiable by shrinking the segments.
+	t.Run("required", func(t *testing.T) {
+		layout := Horizontal(Len(3), Relative{Index: 0, Scale: 2, Strength: casso.Required}, Len(8)).WithFlex(FlexStart)
+
+		for _, width := range []int{20, 10, 0} {
+			segments, _, err := layout.TrySplit(uv.Rect(0, 0, width, 1))
+			require.NoError(t, err)
+
+			// Sizes are rounded to cells.
+			require.InDelta(t, 2*segments[0].Dx(), segments[1].Dx(), 1, "width %d", width)
+		}
+	})
+}
+
+func TestGrouped(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "pair",
+			Width:       10,
+			Constraints: []Constraint{Grouped{Len(3), "g", 0}, Fill(1), Grouped{Min(1), "g", 0}},
+			Want:        "aaabbbbccc",
+		},
+		{
+			Name:  "every other segment",
+			Flex:  FlexStart,
+			Width: 10,
+			Constraints: []Constraint{
+				Len(1), Grouped{Len(2), "g", 0},
+				Len(1), Grouped{Fill(1), "g", 0},
+				Len(1), Grouped{Percentage(10), "g", 0},
+			},
+			Want: "abbcddeff ",
+		},
+		{
+			Name:  "several groups",
+			Flex:  FlexStart,
+			Width: 10,
+			Constraints: []Constraint{
+				Grouped{Len(4), "a", 0},
+				Grouped{Len(1), "b", 0},
+				Grouped{Len(2), "a", casso.Weak},
+				Grouped{Len(1), "b", 0},
+			},
+			Want: "aaaabccd  ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+
+	t.Run("measured", func(t *testing.T) {
+		layout := Horizontal(Grouped{Auto(func(int) int { return 3 }), "g", 0}, Fill(1), Grouped{Fill(1), "g", 0})
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 3, 1),
+			uv.Rect(3, 0, 4, 1),
+			uv.Rect(7, 0, 3, 1),
+		}, layout.Split(uv.Rect(0, 0, 10, 1)))
+	})
+}
+
+func TestGaps(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "join and gap",
+			Flex:        FlexStart,
+			Spacing:     SpacingSpace(1),
+			Gaps:        []Constraint{Len(0), Len(2)},
+			Width:       20,
+			Constraints: []Constraint{Fill(1), Len(2), Len(5)},
+			Want:        "aaaaaaaaaaabb  ccccc",
+		},
+		{
+			Name:        "default spacing",
+			Flex:        FlexStart,
+			Spacing:     SpacingSpace(1),
+			Gaps:        []Constraint{Fill(1)},
+			Width:       10,
+			Constraints: []Constraint{Len(2), Len(2), Len(2)},
+			Want:        "aa   bb cc",
+		},
+		{
+			Name:        "min",
+			Flex:        FlexStart,
+			Gaps:        []Constraint{Min(2)},
+			Width:       10,
+			Constraints: []Constraint{Len(3), Len(3)},
+			Want:        "aaa    bbb",
+		},
+		{
+			Name:        "fill proportions",
+			Flex:        FlexStart,
+			Gaps:        []Constraint{Fill(1), Fill(2)},
+			Width:       9,
+			Constraints: []Constraint{Len(1), Len(1), Len(1)},
+			Want:        "a  b    c",
+		},
+		{
+			Name:        "hidden",
+			Flex:        FlexStart,
+			Gaps:        []Constraint{Len(3), Len(1)},
+			Width:       10,
+			Constraints: []Constraint{Len(2), Hidden{Len(2)}, Len(2)},
+			Want:        "aa cc     ",
+		},
+		{
+			Name:        "legacy",
+			Flex:        FlexLegacy,
+			Spacing:     SpacingSpace(1),
+			Gaps:        []Constraint{nil, Len(3)},
+			Width:       12,
+			Constraints: []Constraint{Len(2), Len(2), Fill(1)},
+			Want:        "aa bb   cccc",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		cache := NewCache(10)
+		layout := Horizontal(Len(2), Len(2)).WithFlex(FlexStart).WithCache(cache)
+		area := uv.Rect(0, 0, 10, 1)
+
+		require.Equal(t, uv.Rect(2, 0, 2, 1), layout.Split(area)[1])
+		require.Equal(t, uv.Rect(5, 0, 2, 1), layout.WithGaps(Len(3)).Split(area)[1])
+		require.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("measure", func(t *testing.T) {
+		layout := Horizontal(Len(2), Len(2), Len(2)).WithSpacing(SpacingSpace(1)).WithGaps(Len(0))
+
+		require.Equal(t, Measurement{Min: 7, Preferred: 7}, layout.Measure(DirectionHorizontal, 1))
+	})
+
+	t.Run("prioritized", func(t *testing.T) {
+		layout := Horizontal(Len(4), Prioritized{Constraint: Len(4)}).WithGaps(Len(2))
+
+		_, _, hidden, err := layout.TrySplitHidden(uv.Rect(0, 0, 10, 1))
+		require.NoError(t, err)
+		require.Nil(t, hidden)
+
+		_, _, hidden, err = layout.TrySplitHidden(uv.Rect(0, 0, 9, 1))
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, hidden)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for _, gap := range []Constraint{
+			Auto(func(int) int { return 1 }),
+			Measured{Measurer: Horizontal(Len(2))},
+			Relative{Index: 0},
+			Grouped{Constraint: Len(1), Group: "a"},
+			Hidden{Constraint: Len(1)},
+		} {
+			t.Run(gap.String(), func(t *testing.T) {
+				layout := Horizontal(Len(2), Len(2), Len(2)).WithGaps(nil, gap)
+
+				_, _, err := layout.TrySplit(uv.Rect(0, 0, 10, 1))
+
+				var gapErr *GapError
+				require.ErrorAs(t, err, &gapErr)
+				require.Equal(t, 1, gapErr.Index)
+				require.Equal(t, gap.String(), gapErr.Constraint.String())
+			})
+		}
+	})
+
+	t.Run("unsupported in system", func(t *testing.T) {
+		_, err := NewSystem().
+			WithLayout("top", Horizontal(Len(2), Len(2)).WithGaps(Auto(func(int) int { return 1 })), uv.Rect(0, 0, 10, 1)).
+			TrySplit()
+
+		var gapErr *GapError
+		require.ErrorAs(t, err, &gapErr)
+	})
+}
+
+func TestHidden(t *testing.T) {
+	testCases := []LayoutSplitTestCase{
+		{
+			Name:        "no double spacing",
+			Flex:        FlexStart,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Len(3), Hidden{Len(5)}, Len(3)},
+			Want:        "aaa ccc   ",
+		},
+		{
+			Name:        "empty segment keeps spacing",
+			Flex:        FlexStart,
+			Spacing:     SpacingSpace(1),
+			Width:       10,
+			Constraints: []Constraint{Len(3), Len(0), Len(3)},
+			Want:        "aaa  ccc  ",
+		},
+		{
+			Name:        "first hidden",
+			Flex:        FlexSpaceBetween,
+			Width:       10,
+			Constraints: []Constraint{Hidden{Len(2)}, Len(2), Len(2)},
+			Want:        "bb      cc",
+		},
+		{
+			Name:        "last hidden",
+			Flex:        FlexSpaceAround,
+			Spacing:     SpacingSpace(0),
+			Width:       12,
+			Constraints: []Constraint{Len(2), Len(2), Hidden{Fill(1)}},
+			Want:        "aa        bb",
+		},
+		{
+			Name:        "legacy",
+			Flex:        FlexLegacy,
+			Width:       10,
+			Constraints: []Constraint{Len(2), Hidden{Fill(1)}, Fill(1)},
+			Want:        "aacccccccc",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, tc.Test)
+	}
+
+	t.Run("keeps indexes", func(t *testing.T) {
+		layout := Horizontal(Len(3), Hidden{Len(5)}, Len(3)).WithSpacing(SpacingSpace(1)).WithFlex(FlexStart)
+
+		segments, spacers := layout.SplitWithSpacers(uv.Rect(0, 0, 10, 1))
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 3, 1),
+			uv.Rect(3, 0, 0, 1),
+			uv.Rect(4, 0, 3, 1),
+		}, segments)
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 0, 1),
+			uv.Rect(3, 0, 0, 1),
+			uv.Rect(3, 0, 1, 1),
+			uv.Rect(7, 0, 3, 1),
+		}, spacers)
+
+		_, _, hidden, err := layout.TrySplitHidden(uv.Rect(0, 0, 10, 1))
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, hidden)
+	})
+
+	t.Run("measure", func(t *testing.T) {
+		layout := Horizontal(Len(3), Hidden{Len(5)}, Len(3)).WithSpacing(SpacingSpace(1))
+
+		require.Equal(t, Measurement{Min: 7, Preferred: 7}, layout.Measure(DirectionHorizontal, 1))
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		layout := Horizontal(
+			Grouped{Constraint: Hidden{Len(4)}, Group: "g"},
+			Grouped{Constraint: Len(6), Group: "g"},
+		).WithFlex(FlexStart)
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 0, 1),
+			uv.Rect(0, 0, 6, 1),
+		}, layout.Split(uv.Rect(0, 0, 20, 1)))
+
+		layout = Horizontal(Len(2), Prioritized{Constraint: Hidden{Len(4)}}, Len(2)).
+			WithSpacing(SpacingSpace(2)).
+			WithFlex(FlexStart)
+
+		segments, _, hidden, err := layout.TrySplitHidden(uv.Rect(0, 0, 20, 1))
+		require.NoError(t, err)
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 2, 1),
+			uv.Rect(2, 0, 0, 1),
+			uv.Rect(4, 0, 2, 1),
+		}, segments)
+		require.Equal(t, []int{1}, hidden)
+	})
+}
+
 type Rect = uv.Rectangle
 
 func TestEdgeCases(t *testing.T) {
@@ -1207,7 +2007,197 @@ func TestFlexConstraint(t *testing.T) {
 	}
 }
 
-func letters(t *testing.T, flex Flex, constraints []Constraint, width int, expected string) {
+func TestTrySplit(t *testing.T) {
+	layout := Horizontal(Len(3), Fill(1), Len(3)).WithSpacing(SpacingSpace(1))
+	area := uv.Rect(0, 0, 20, 1)
+
+	segments, spacers, err := layout.TrySplit(area)
+	require.NoError(t, err)
+
+	wantSegments, wantSpacers := layout.SplitWithSpacers(area)
+
+	require.Equal(t, wantSegments, segments)
+	require.Equal(t, wantSpacers, spacers)
+}
+
+func TestWrapSolverError(t *testing.T) {
+	t.Run("unsatisfiable", func(t *testing.T) {
+		err := wrapSolverError(fmt.Errorf("add constraint: %w", casso.ErrUnsatisfiableConstraint))
+
+		var target *UnsatisfiableConstraintError
+
+		require.ErrorAs(t, err, &target)
+		require.ErrorIs(t, err, casso.ErrUnsatisfiableConstraint)
+	})
+
+	t.Run("unsatisfiable with conflicts", func(t *testing.T) {
+		solver := casso.NewSolver()
+		x := solver.NewVariable("x")
+
+		atLeast := casso.GreaterThanEqual(casso.Required).VariableLHS(x).ConstantRHS(10)
+		atMost := casso.LessThanEqual(casso.Required).VariableLHS(x).ConstantRHS(5)
+
+		require.NoError(t, solver.AddConstraint(atLeast))
+
+		err := wrapSolverError(fmt.Errorf("configure constraints: %w", solver.AddConstraint(atMost)))
+
+		var target *UnsatisfiableConstraintError
+
+		require.ErrorAs(t, err, &target)
+
+		var unsatisfiable *casso.UnsatisfiableConstraintError
+
+		require.ErrorAs(t, err, &unsatisfiable)
+		require.Len(t, unsatisfiable.Conflicts, 2)
+		require.Equal(
+			t,
+			"uvcasso: configure constraints: unsatisfiable constraint: x >= 10 (required); x <= 5 (required)",
+			err.Error(),
+		)
+	})
+
+	t.Run("internal", func(t *testing.T) {
+		err := wrapSolverError(fmt.Errorf("optimize: %w", casso.InternalSolverError("unbounded objective")))
+
+		var target *InternalSolverError
+
+		require.ErrorAs(t, err, &target)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		require.NoError(t, wrapSolverError(nil))
+	})
+}
+
+func TestManySegments(t *testing.T) {
+	testCases := []struct {
+		name        string
+		count       int
+		constraint  func(i int) Constraint
+		flex        Flex
+		segmentSize int
+	}{
+		{
+			name:        "fill",
+			count:       24,
+			constraint:  func(int) Constraint { return Fill(1) },
+			flex:        FlexLegacy,
+			segmentSize: 3,
+		},
+		{
+			name:  "fill and len",
+			count: 120,
+			constraint: func(i int) Constraint {
+				if i%4 == 0 {
+					return Fill(1)
+				}
+
+				return Len(3)
+			},
+			flex:        FlexStart,
+			segmentSize: 3,
+		},
+		{
+			name:        "len",
+			count:       150,
+			constraint:  func(int) Constraint { return Len(3) },
+			flex:        FlexStart,
+			segmentSize: 3,
+		},
+		{
+			name:        "len centered",
+			count:       100,
+			constraint:  func(int) Constraint { return Len(2) },
+			flex:        FlexCenter,
+			segmentSize: 2,
+		},
+		{
+			name:  "mixed",
+			count: 120,
+			constraint: func(i int) Constraint {
+				switch i % 4 {
+				case 0:
+					return Len(1)
+				case 1:
+					return Min(1)
+				case 2:
+					return Max(3)
+				default:
+					return Percentage(1)
+				}
+			},
+			flex: FlexLegacy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %d", tc.name, tc.count), func(t *testing.T) {
+			constraints := make([]Constraint, tc.count)
+			for i := range constraints {
+				constraints[i] = tc.constraint(i)
+			}
+
+			area := uv.Rect(0, 0, tc.count*3, 1)
+
+			segments, spacers := Horizontal(constraints...).
+				WithFlex(tc.flex).
+				WithoutCache().
+				SplitWithSpacers(area)
+
+			require.Len(t, segments, tc.count)
+			require.Len(t, spacers, tc.count+1)
+
+			// Spacers and segments interleave and cover the whole area.
+			require.Equal(t, area.Min.X, spacers[0].Min.X)
+			require.Equal(t, area.Max.X, spacers[tc.count].Max.X)
+
+			for i, segment := range segments {
+				require.Equal(t, spacers[i].Max.X, segment.Min.X, "segment %d", i)
+				require.Equal(t, segment.Max.X, spacers[i+1].Min.X, "segment %d", i)
+
+				if tc.segmentSize > 0 {
+					require.Equal(t, tc.segmentSize, segment.Dx(), "segment %d", i)
+				}
+
+				switch c := constraints[i].(type) {
+				case Len:
+					require.Equal(t, int(c), segment.Dx(), "segment %d", i)
+				case Min:
+					require.GreaterOrEqual(t, segment.Dx(), int(c), "segment %d", i)
+				case Max:
+					require.LessOrEqual(t, segment.Dx(), int(c), "segment %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitDeterministic(t *testing.T) {
+	layouts := []Layout{
+		Horizontal(Fill(1), Fill(2), Min(3), Max(5), Percentage(25), Ratio{Num: 1, Den: 7}, Len(4)),
+		Horizontal(Fill(1), Fill(1), Fill(1), Len(2)).WithFlex(FlexSpaceBetween).WithSpacing(SpacingSpace(1)),
+		Vertical(Min(2), Min(2), Min(2), Max(1)).WithFlex(FlexSpaceAround),
+		Horizontal(Percentage(33), Percentage(33), Percentage(33)).WithFlex(FlexCenter),
+	}
+
+	for i, layout := range layouts {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			layout := layout.WithoutCache()
+			area := uv.Rect(0, 0, 37, 23)
+
+			segments, spacers := layout.SplitWithSpacers(area)
+			want := fmt.Sprint(segments, spacers)
+
+			for range 2000 {
+				segments, spacers := layout.SplitWithSpacers(area)
+
+				require.Equal(t, want, fmt.Sprint(segments, spacers))
+			}
+		})
+	}
+}
+
+func letters(t *testing.T, flex Flex, spacing Spacing, gaps []Constraint, constraints []Constraint, width int, expected string) {
 	t.Helper()
 
 	area := uv.Rect(0, 0, width, 1)
@@ -1216,7 +2206,8 @@ func letters(t *testing.T, flex Flex, constraints []Constraint, width int, expec
 		Direction:   DirectionHorizontal,
 		Constraints: constraints,
 		Flex:        flex,
-		Spacing:     SpacingSpace(0),
+		Spacing:     spacing,
+		Gaps:        gaps,
 	}.Split(area)
 
 	got := uv.NewScreenBuffer(area.Dx(), area.Dy())
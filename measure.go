@@ -0,0 +1,197 @@
+package uvcasso
+
+import (
+	"fmt"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// Measurement is the intrinsic size of content along some axis.
+type Measurement struct {
+	// Min is the size below which the content can not shrink.
+	Min int
+
+	// Preferred is the size which fits the content entirely.
+	Preferred int
+}
+
+// Measurer reports the intrinsic size of content.
+//
+// Measure is called with the direction of the axis to measure along and
+// the size available along the other axis, e.g. a text block measured
+// in [DirectionVertical] returns its height when wrapped to the cross width.
+type Measurer interface {
+	Measure(direction Direction, cross int) Measurement
+}
+
+// MeasureFunc is a function implementing [Measurer].
+type MeasureFunc func(direction Direction, cross int) Measurement
+
+func (f MeasureFunc) Measure(direction Direction, cross int) Measurement {
+	return f(direction, cross)
+}
+
+// Measured sizes the segment by its content.
+//
+// The segment never shrinks below the measured minimum, like [Min],
+// and otherwise prefers the measured preferred size, like [Auto].
+//
+// Nested layouts implement [Measurer], so a layout can be sized by its children:
+//
+//	Vertical(Measured{Horizontal(...)}, Fill(1))
+type Measured struct {
+	Measurer Measurer
+}
+
+func (Measured) String() string { return "Measured" }
+func (Measured) isConstraint()  {}
+
+// _MeasuredLen is the measurement of a [Measured] constraint.
+type _MeasuredLen Measurement
+
+func (m _MeasuredLen) String() string { return fmt.Sprintf("Measured(%d, %d)", m.Min, m.Preferred) }
+func (_MeasuredLen) isConstraint()    {}
+
+// Measure implements [Measurer].
+//
+// Along the direction of the layout the measurements of the visible segments
+// are summed up together with the spacing or the gaps between them. Along
+// the other direction the cross size is split between the segments first,
+// then each [Measured] segment is measured with its share and the largest
+// measurement is used. Segments [Aligned] with a Size are measured by the
+// Size instead.
+//
+// Padding is added to the measurement and removed from the cross size.
+func (l Layout) Measure(direction Direction, cross int) Measurement {
+	mainPadding := l.Padding.Top + l.Padding.Bottom
+	crossPadding := l.Padding.Left + l.Padding.Right
+
+	if direction == DirectionHorizontal {
+		mainPadding, crossPadding = crossPadding, mainPadding
+	}
+
+	cross = max(0, cross-crossPadding)
+
+	var m Measurement
+
+	if direction == l.Direction {
+		spacing := spacingSize(l.Spacing)
+
+		var visible int
+
+		for i, c := range l.Constraints {
+			if isHidden(c) {
+				continue
+			}
+
+			if visible > 0 {
+				gap := Measurement{Min: spacing, Preferred: spacing}
+				if c := l.gapBefore(i); c != nil {
+					gap = measureConstraint(c, direction, cross)
+				}
+
+				m.Min += gap.Min
+				m.Preferred += gap.Preferred
+			}
+
+			segment := measureConstraint(c, direction, cross)
+
+			m.Min += segment.Min
+			m.Preferred += segment.Preferred
+
+			visible++
+		}
+
+		m.Min = max(0, m.Min)
+		m.Preferred = max(0, m.Preferred)
+	} else {
+		var area uv.Rectangle
+
+		switch l.Direction {
+		case DirectionHorizontal:
+			area = uv.Rect(0, 0, cross, 0)
+		case DirectionVertical:
+			area = uv.Rect(0, 0, 0, cross)
+		}
+
+		// An unsatisfiable layout has no intrinsic size, segments stay empty.
+		segments, _, _ := l.WithPadding(NewPadding()).TrySplit(area)
+
+		for i, c := range l.Constraints {
+			if i >= len(segments) {
+				break
+			}
+
+			size := segments[i].Dx()
+			if l.Direction == DirectionVertical {
+				size = segments[i].Dy()
+			}
+
+			var segment Measurement
+
+			if aligned, ok := findAligned(c); ok && aligned.Size != nil {
+				segment = measureConstraint(aligned.Size, direction, size)
+			} else if measured, ok := baseConstraint(c).(Measured); ok && measured.Measurer != nil {
+				segment = measured.Measurer.Measure(direction, size)
+			}
+
+			m.Min = max(m.Min, segment.Min)
+			m.Preferred = max(m.Preferred, segment.Preferred)
+		}
+	}
+
+	m.Min += mainPadding
+	m.Preferred += mainPadding
+
+	return m
+}
+
+// measureConstraint returns the intrinsic size of a segment along the
+// direction of its layout.
+func measureConstraint(c Constraint, direction Direction, cross int) Measurement {
+	switch c := baseConstraint(c).(type) {
+	case Len:
+		return Measurement{Min: int(c), Preferred: int(c)}
+
+	case Min:
+		return Measurement{Min: int(c), Preferred: int(c)}
+
+	case Max:
+		return Measurement{Min: 0, Preferred: int(c)}
+
+	case Calc:
+		return Measurement{Min: c.Min, Preferred: c.Min}
+
+	case Auto:
+		if c == nil {
+			return Measurement{}
+		}
+
+		return Measurement{Min: 0, Preferred: max(0, c(cross))}
+
+	case Measured:
+		return measure(c, direction, cross)
+
+	case _AutoLen:
+		return Measurement{Min: 0, Preferred: int(c)}
+
+	case _MeasuredLen:
+		return Measurement(c)
+
+	default:
+		return Measurement{}
+	}
+}
+
+func measure(c Measured, direction Direction, cross int) Measurement {
+	if c.Measurer == nil {
+		return Measurement{}
+	}
+
+	m := c.Measurer.Measure(direction, cross)
+
+	m.Min = max(0, m.Min)
+	m.Preferred = max(m.Min, m.Preferred)
+
+	return m
+}
@@ -0,0 +1,95 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+// text measures a text of the given length wrapped to the cross size.
+func text(length int) Measurer {
+	return MeasureFunc(func(direction Direction, cross int) Measurement {
+		if direction == DirectionHorizontal {
+			return Measurement{Min: 1, Preferred: length}
+		}
+
+		if cross <= 0 {
+			return Measurement{}
+		}
+
+		height := (length + cross - 1) / cross
+
+		return Measurement{Min: height, Preferred: height}
+	})
+}
+
+func TestMeasured(t *testing.T) {
+	t.Run("height for width", func(t *testing.T) {
+		layout := Vertical(Measured{text(30)}, Measured{text(12)}, Fill(1))
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 10, 3),
+			uv.Rect(0, 3, 10, 2),
+			uv.Rect(0, 5, 10, 5),
+		}, layout.Split(uv.Rect(0, 0, 10, 10)))
+	})
+
+	t.Run("nested layout", func(t *testing.T) {
+		row := Horizontal(Len(5), Measured{text(30)}).WithSpacing(SpacingSpace(1))
+		layout := Vertical(Measured{row}, Fill(1))
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 16, 3),
+			uv.Rect(0, 3, 16, 7),
+		}, layout.Split(uv.Rect(0, 0, 16, 10)))
+	})
+
+	t.Run("min wins over length", func(t *testing.T) {
+		content := MeasureFunc(func(Direction, int) Measurement {
+			return Measurement{Min: 4, Preferred: 6}
+		})
+
+		layout := Horizontal(Measured{content}, Len(8))
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 4, 1),
+			uv.Rect(4, 0, 6, 1),
+		}, layout.Split(uv.Rect(0, 0, 10, 1)))
+	})
+
+	t.Run("nil measurer", func(t *testing.T) {
+		layout := Horizontal(Measured{}, Len(2)).WithFlex(FlexStart)
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 0, 1),
+			uv.Rect(0, 0, 2, 1),
+		}, layout.Split(uv.Rect(0, 0, 10, 1)))
+	})
+}
+
+func TestLayoutMeasure(t *testing.T) {
+	t.Run("along the layout", func(t *testing.T) {
+		layout := Vertical(Len(2), Measured{text(30)}, Max(3), Fill(1)).
+			WithSpacing(SpacingSpace(1)).
+			WithPadding(NewPadding(1))
+
+		require.Equal(t, Measurement{Min: 2 + 3 + 3 + 2, Preferred: 2 + 3 + 3 + 3 + 2}, layout.Measure(DirectionVertical, 12))
+	})
+
+	t.Run("across the layout", func(t *testing.T) {
+		layout := Horizontal(Len(5), Measured{text(30)}, Len(3)).
+			WithPadding(NewPadding(1))
+
+		// The text is wrapped to 20 - 2 - 5 - 3 = 10 cells.
+		m := layout.Measure(DirectionVertical, 20)
+
+		require.Equal(t, Measurement{Min: 3 + 2, Preferred: 3 + 2}, m)
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		layout := Horizontal(Len(3), Len(3), Len(3)).WithSpacing(SpacingOverlap(1))
+
+		require.Equal(t, Measurement{Min: 7, Preferred: 7}, layout.Measure(DirectionHorizontal, 1))
+	})
+}
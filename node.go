@@ -0,0 +1,325 @@
+package uvcasso
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/metafates/uvcasso/casso"
+)
+
+// _paddingEq binds the area of a layout to the padded area of its node.
+// It is not required so that a node smaller than its padding does not
+// make the whole tree unsatisfiable.
+const _paddingEq casso.Strength = casso.Required - 1
+
+// Node is a tree of layouts solved as a single problem.
+//
+// A branch splits its area between the children with its Layout,
+// the constraint at index i sizes the child at index i. A node without
+// children is a leaf.
+//
+// Unlike nested [Layout.Split] calls, constraints propagate between levels,
+// e.g. a [Min] constraint of a nested row can widen its parent column.
+type Node struct {
+	// ID identifies the node in the solved result. Nodes without ID are
+	// solved as usual but not reported.
+	ID string
+
+	Layout   Layout
+	Children []Node
+}
+
+// Leaf creates a node without children.
+func Leaf(id string) Node {
+	return Node{ID: id}
+}
+
+// Branch creates a node splitting its area between the children with the given layout.
+func Branch(layout Layout, children ...Node) Node {
+	return Node{
+		Layout:   layout,
+		Children: children,
+	}
+}
+
+func (n Node) WithID(id string) Node {
+	n.ID = id
+	return n
+}
+
+// TrySolve solves the tree for the given area and returns
+// the rectangles of the nodes by their IDs.
+//
+// Returns [ErrDuplicateNodeID] if IDs are not unique, [ErrTooManyChildren]
+// if a branch has more children than constraints, and otherwise the same
+// errors as [Layout.TrySplit].
+//
+// [Auto] and [Measured] constraints are first measured with the cross size of
+// the given area. The tree is then solved once more with the measurements
+// taken from the cross sizes of the first solution. [Prioritized] constraints
+// are resolved the same way.
+func (n Node) TrySolve(area uv.Rectangle) (map[string]uv.Rectangle, error) {
+	if err := n.validate(make(map[string]struct{})); err != nil {
+		return nil, err
+	}
+
+	rects, err := n.solve(area, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.measures() {
+		rects, err = n.solve(area, rects)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[string]uv.Rectangle)
+
+	n.walk(func(index int, node Node) {
+		if node.ID != "" {
+			result[node.ID] = rects[index]
+		}
+	})
+
+	return result, nil
+}
+
+// Solve solves the tree for the given area.
+//
+// It panics on error, see [Node.TrySolve].
+func (n Node) Solve(area uv.Rectangle) map[string]uv.Rectangle {
+	rects, err := n.TrySolve(area)
+	if err != nil {
+		panic(err)
+	}
+
+	return rects
+}
+
+func (n Node) validate(ids map[string]struct{}) error {
+	if n.ID != "" {
+		if _, ok := ids[n.ID]; ok {
+			return fmt.Errorf("%w: %q", ErrDuplicateNodeID, n.ID)
+		}
+
+		ids[n.ID] = struct{}{}
+	}
+
+	if len(n.Children) > len(n.Layout.Constraints) {
+		return fmt.Errorf(
+			"%w: node %q has %d children and %d constraints",
+			ErrTooManyChildren, n.ID, len(n.Children), len(n.Layout.Constraints),
+		)
+	}
+
+	for _, child := range n.Children {
+		if err := child.validate(ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// measures reports whether any layout of the tree has measured
+// or prioritized constraints, which depend on the area of the layout.
+func (n Node) measures() bool {
+	measures := slices.ContainsFunc(n.Layout.Constraints, func(c Constraint) bool {
+		switch baseConstraint(c).(type) {
+		case Auto, Measured:
+			return true
+		default:
+			return isPrioritized(c)
+		}
+	})
+
+	return measures || slices.ContainsFunc(n.Children, Node.measures)
+}
+
+// walk calls f for every node of the tree in depth-first order.
+func (n Node) walk(f func(index int, node Node)) {
+	var index int
+
+	var visit func(node Node)
+
+	visit = func(node Node) {
+		f(index, node)
+		index++
+
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+
+	visit(n)
+}
+
+// _Tree is a tree being solved.
+type _Tree struct {
+	solver *casso.Solver
+	area   uv.Rectangle
+
+	// previous are the rectangles of the nodes from the previous solution
+	// in depth-first order, used to measure the constraints.
+	previous []uv.Rectangle
+
+	// elements are the horizontal and vertical bounds of the nodes in depth-first order.
+	elements [][2]_Element
+}
+
+func (n Node) solve(area uv.Rectangle, previous []uv.Rectangle) ([]uv.Rectangle, error) {
+	tree := _Tree{
+		solver:   casso.NewSolver(),
+		area:     area,
+		previous: previous,
+	}
+
+	x := _Element{
+		Start: tree.solver.NewVariable("x.start"),
+		End:   tree.solver.NewVariable("x.end"),
+	}
+
+	y := _Element{
+		Start: tree.solver.NewVariable("y.start"),
+		End:   tree.solver.NewVariable("y.end"),
+	}
+
+	err := configureArea(
+		tree.solver,
+		x,
+		float64(area.Min.X)*_floatPrecisionMultiplier,
+		float64(area.Max.X)*_floatPrecisionMultiplier,
+	)
+	if err != nil {
+		return nil, wrapSolverError(fmt.Errorf("configure area: %w", err))
+	}
+
+	err = configureArea(
+		tree.solver,
+		y,
+		float64(area.Min.Y)*_floatPrecisionMultiplier,
+		float64(area.Max.Y)*_floatPrecisionMultiplier,
+	)
+	if err != nil {
+		return nil, wrapSolverError(fmt.Errorf("configure area: %w", err))
+	}
+
+	if err := tree.configure(n, "root", x, y); err != nil {
+		return nil, wrapSolverError(err)
+	}
+
+	changes := make([]float64, tree.solver.VariableCount())
+	for _, c := range tree.solver.FetchChanges() {
+		changes[c.Variable] = c.Constant
+	}
+
+	rects := make([]uv.Rectangle, 0, len(tree.elements))
+
+	for _, e := range tree.elements {
+		x, y := e[0], e[1]
+
+		minX, maxX := fromSolverValue(changes[x.Start]), fromSolverValue(changes[x.End])
+		minY, maxY := fromSolverValue(changes[y.Start]), fromSolverValue(changes[y.End])
+
+		rects = append(rects, uv.Rect(minX, minY, max(0, maxX-minX), max(0, maxY-minY)))
+	}
+
+	return rects, nil
+}
+
+// configure adds the constraints of the node bounded by x and y and of all its children.
+func (t *_Tree) configure(n Node, path string, x, y _Element) error {
+	index := len(t.elements)
+	t.elements = append(t.elements, [2]_Element{x, y})
+
+	if len(n.Children) == 0 {
+		return nil
+	}
+
+	if n.ID != "" {
+		path = n.ID
+	}
+
+	prefix := path + "."
+
+	l := n.Layout
+	padding := l.Padding
+
+	main, cross := y, x
+	mainPadding := [2]int{padding.Top, padding.Bottom}
+	crossPadding := [2]int{padding.Left, padding.Right}
+
+	if l.Direction == DirectionHorizontal {
+		main, cross = x, y
+		mainPadding, crossPadding = crossPadding, mainPadding
+	}
+
+	area := t.area
+	if t.previous != nil {
+		area = t.previous[index]
+	}
+
+	l = l.measure(area)
+
+	variables := newVariables(t.solver, prefix, len(l.Constraints))
+
+	layoutArea := _Element{
+		Start: variables[0],
+		End:   variables[len(variables)-1],
+	}
+
+	if err := t.inset(layoutArea, main, mainPadding); err != nil {
+		return fmt.Errorf("configure %s area: %w", path, err)
+	}
+
+	if err := configureLayout(t.solver, l, variables); err != nil {
+		return fmt.Errorf("configure %s layout: %w", path, err)
+	}
+
+	segments := newElements(variables[1:])
+
+	for i, child := range n.Children {
+		childPath := prefix + "child[" + strconv.Itoa(i) + "]"
+
+		childCross := _Element{
+			Start: t.solver.NewVariable(childPath + ".cross.start"),
+			End:   t.solver.NewVariable(childPath + ".cross.end"),
+		}
+
+		if err := t.inset(childCross, cross, crossPadding); err != nil {
+			return fmt.Errorf("configure %s cross area: %w", childPath, err)
+		}
+
+		childX, childY := childCross, segments[i]
+		if l.Direction == DirectionHorizontal {
+			childX, childY = segments[i], childCross
+		}
+
+		if err := t.configure(child, childPath, childX, childY); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inset binds the inner element to the outer one shrunk by the padding.
+func (t *_Tree) inset(inner, outer _Element, padding [2]int) error {
+	start := casso.Equal(_paddingEq).
+		VariableLHS(inner.Start).
+		ExpressionRHS(casso.NewExpression(float64(padding[0])*_floatPrecisionMultiplier, outer.Start.Mul(1)))
+
+	end := casso.Equal(_paddingEq).
+		VariableLHS(inner.End).
+		ExpressionRHS(casso.NewExpression(-float64(padding[1])*_floatPrecisionMultiplier, outer.End.Mul(1)))
+
+	if err := t.solver.AddConstraints(start, end); err != nil {
+		return fmt.Errorf("add constraints: %w", err)
+	}
+
+	return nil
+}
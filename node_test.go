@@ -0,0 +1,81 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode(t *testing.T) {
+	t.Run("matches nested splits", func(t *testing.T) {
+		body := Horizontal(Len(10), Fill(1)).WithSpacing(SpacingSpace(1))
+		root := Vertical(Len(1), Fill(1), Len(1)).WithPadding(NewPadding(0, 1))
+
+		tree := Branch(root,
+			Leaf("header"),
+			Branch(body, Leaf("nav"), Leaf("main")).WithID("body"),
+			Leaf("footer"),
+		)
+
+		area := uv.Rect(0, 0, 40, 12)
+
+		var header, bodyArea, footer, nav, main uv.Rectangle
+
+		root.Split(area).Assign(&header, &bodyArea, &footer)
+		body.Split(bodyArea).Assign(&nav, &main)
+
+		require.Equal(t, map[string]uv.Rectangle{
+			"header": header,
+			"body":   bodyArea,
+			"nav":    nav,
+			"main":   main,
+			"footer": footer,
+		}, tree.Solve(area))
+	})
+
+	t.Run("child widens parent", func(t *testing.T) {
+		sidebar := Branch(
+			Vertical(Len(1), Len(3)),
+			Leaf("title"),
+			Branch(Horizontal(Min(15)), Leaf("search")),
+		).WithID("sidebar")
+
+		tree := Branch(Horizontal(Len(10), Fill(1)), sidebar, Leaf("main"))
+
+		require.Equal(t, map[string]uv.Rectangle{
+			"sidebar": uv.Rect(0, 0, 15, 10),
+			"title":   uv.Rect(0, 0, 15, 1),
+			"search":  uv.Rect(0, 1, 15, 9),
+			"main":    uv.Rect(15, 0, 25, 10),
+		}, tree.Solve(uv.Rect(0, 0, 40, 10)))
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		tree := Branch(Vertical(Fill(1)).WithPadding(NewPadding(1, 2)), Leaf("content"))
+
+		require.Equal(t, uv.Rect(2, 1, 6, 3), tree.Solve(uv.Rect(0, 0, 10, 5))["content"])
+	})
+
+	t.Run("measured with solved width", func(t *testing.T) {
+		column := Branch(Vertical(Measured{text(30)}, Fill(1)), Leaf("text"), Leaf("rest"))
+		tree := Branch(Horizontal(Len(10), Fill(1)), column, Leaf("main"))
+
+		rects := tree.Solve(uv.Rect(0, 0, 40, 10))
+
+		require.Equal(t, uv.Rect(0, 0, 10, 3), rects["text"])
+		require.Equal(t, uv.Rect(0, 3, 10, 7), rects["rest"])
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		_, err := Branch(Horizontal(Fill(1), Fill(1)), Leaf("a"), Leaf("a")).TrySolve(uv.Rect(0, 0, 10, 1))
+
+		require.ErrorIs(t, err, ErrDuplicateNodeID)
+	})
+
+	t.Run("too many children", func(t *testing.T) {
+		_, err := Branch(Horizontal(Fill(1)), Leaf("a"), Leaf("b")).TrySolve(uv.Rect(0, 0, 10, 1))
+
+		require.ErrorIs(t, err, ErrTooManyChildren)
+	})
+}
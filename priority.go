@@ -0,0 +1,154 @@
+package uvcasso
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Prioritized lets the segment be dropped when the area is too small.
+//
+// When the minimum sizes of the segments and the gaps between them do not
+// fit the area, prioritized segments are hidden one by one, lowest Priority
+// first and the last one first among equal priorities, until the rest fits.
+// A hidden segment is empty and the spacing around it goes away.
+//
+// MinSize is the smallest size the segment is useful at. When zero, the
+// minimum of the wrapped Constraint is used, e.g. the size of [Len].
+type Prioritized struct {
+	Constraint Constraint
+	Priority   int
+	MinSize    int
+}
+
+func (p Prioritized) String() string {
+	return fmt.Sprintf("Prioritized(%v, %d, %d)", p.Constraint, p.Priority, p.MinSize)
+}
+func (Prioritized) isConstraint() {}
+
+func (p Prioritized) unwrap() Constraint { return p.Constraint }
+
+func (p Prioritized) wrap(c Constraint) Constraint {
+	p.Constraint = c
+	return p
+}
+
+// hiddenIndexes returns the indexes of the hidden constraints.
+func hiddenIndexes(constraints []Constraint) []int {
+	var hidden []int
+
+	for i, c := range constraints {
+		if isHidden(c) {
+			hidden = append(hidden, i)
+		}
+	}
+
+	return hidden
+}
+
+// prioritize returns the layout with [Prioritized] constraints hidden
+// until the minimum sizes fit the main size of the inner area.
+func (l Layout) prioritize(main int) Layout {
+	if !slices.ContainsFunc(l.Constraints, isPrioritized) {
+		return l
+	}
+
+	spacing := spacingSize(l.Spacing)
+
+	sizes := make([]int, len(l.Constraints))
+	for i, c := range l.Constraints {
+		sizes[i] = measureConstraint(c, l.Direction, 0).Min
+
+		if p, ok := findPrioritized(c); ok && p.MinSize != 0 {
+			sizes[i] = p.MinSize
+		}
+	}
+
+	hidden := make([]bool, len(l.Constraints))
+	for i, c := range l.Constraints {
+		hidden[i] = isHidden(c)
+	}
+
+	required := func() int {
+		var size, visible int
+
+		for i := range sizes {
+			if hidden[i] {
+				continue
+			}
+
+			if visible > 0 {
+				if gap := l.gapBefore(i); gap != nil {
+					size += measureConstraint(gap, l.Direction, 0).Min
+				} else {
+					size += spacing
+				}
+			}
+
+			size += sizes[i]
+			visible++
+		}
+
+		return size
+	}
+
+	for required() > main {
+		hide := -1
+
+		var priority int
+
+		for i, c := range l.Constraints {
+			p, ok := findPrioritized(c)
+			if !ok || hidden[i] {
+				continue
+			}
+
+			if hide < 0 || p.Priority <= priority {
+				hide, priority = i, p.Priority
+			}
+		}
+
+		if hide < 0 {
+			break
+		}
+
+		hidden[hide] = true
+	}
+
+	var constraints []Constraint
+
+	for i, c := range l.Constraints {
+		if hidden[i] && !isHidden(c) {
+			// Copy on write, the constraints may be shared with the caller.
+			if constraints == nil {
+				constraints = slices.Clone(l.Constraints)
+			}
+
+			constraints[i] = Hidden{Constraint: c}
+		}
+	}
+
+	if constraints != nil {
+		l.Constraints = constraints
+	}
+
+	return l
+}
+
+func isPrioritized(c Constraint) bool {
+	_, ok := findPrioritized(c)
+	return ok
+}
+
+// findPrioritized returns the outermost [Prioritized] wrapper of the constraint.
+func findPrioritized(c Constraint) (Prioritized, bool) {
+	for {
+		switch w := c.(type) {
+		case Prioritized:
+			return w, true
+		case _wrapper:
+			c = w.unwrap()
+		default:
+			return Prioritized{}, false
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrioritized(t *testing.T) {
+	layout := Horizontal(
+		Prioritized{Constraint: Len(10), Priority: 3},
+		Prioritized{Constraint: Len(8), Priority: 1},
+		Fill(1),
+		Prioritized{Constraint: Len(6), Priority: 2},
+	).WithSpacing(SpacingSpace(1)).WithFlex(FlexStart)
+
+	for _, tc := range []struct {
+		name     string
+		width    int
+		hidden   []int
+		expected []uv.Rectangle
+	}{
+		{
+			name:  "fits",
+			width: 30,
+			expected: []uv.Rectangle{
+				uv.Rect(0, 0, 10, 1),
+				uv.Rect(11, 0, 8, 1),
+				uv.Rect(20, 0, 3, 1),
+				uv.Rect(24, 0, 6, 1),
+			},
+		},
+		{
+			name:   "lowest priority hidden",
+			width:  20,
+			hidden: []int{1},
+			expected: []uv.Rectangle{
+				uv.Rect(0, 0, 10, 1),
+				uv.Rect(10, 0, 0, 1),
+				uv.Rect(11, 0, 2, 1),
+				uv.Rect(14, 0, 6, 1),
+			},
+		},
+		{
+			name:   "two hidden",
+			width:  12,
+			hidden: []int{1, 3},
+			expected: []uv.Rectangle{
+				uv.Rect(0, 0, 10, 1),
+				uv.Rect(10, 0, 0, 1),
+				uv.Rect(11, 0, 1, 1),
+				uv.Rect(12, 0, 0, 1),
+			},
+		},
+		{
+			name:   "all hidden",
+			width:  5,
+			hidden: []int{0, 1, 3},
+			expected: []uv.Rectangle{
+				uv.Rect(0, 0, 0, 1),
+				uv.Rect(0, 0, 0, 1),
+				uv.Rect(0, 0, 5, 1),
+				uv.Rect(5, 0, 0, 1),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			area := uv.Rect(0, 0, tc.width, 1)
+
+			segments, spacers, hidden, err := layout.TrySplitHidden(area)
+			require.NoError(t, err)
+
+			require.Equal(t, Splitted(tc.expected), segments)
+			require.Len(t, spacers, 5)
+			require.Equal(t, tc.hidden, hidden)
+
+			// Cached results keep the hidden indexes.
+			_, _, hidden, err = layout.TrySplitHidden(area)
+			require.NoError(t, err)
+			require.Equal(t, tc.hidden, hidden)
+		})
+	}
+
+	t.Run("min size", func(t *testing.T) {
+		layout := Horizontal(
+			Prioritized{Constraint: Fill(1), Priority: 1, MinSize: 10},
+			Len(5),
+		)
+
+		_, _, hidden, err := layout.TrySplitHidden(uv.Rect(0, 0, 15, 1))
+		require.NoError(t, err)
+		require.Nil(t, hidden)
+
+		_, _, hidden, err = layout.TrySplitHidden(uv.Rect(0, 0, 14, 1))
+		require.NoError(t, err)
+		require.Equal(t, []int{0}, hidden)
+
+		require.Equal(t, Splitted{
+			uv.Rect(0, 0, 0, 1),
+			uv.Rect(0, 0, 14, 1),
+		}, layout.Split(uv.Rect(0, 0, 14, 1)))
+	})
+
+	t.Run("equal priorities drop the last first", func(t *testing.T) {
+		layout := Horizontal(
+			Prioritized{Constraint: Len(4)},
+			Prioritized{Constraint: Len(4)},
+			Prioritized{Constraint: Len(4)},
+		)
+
+		_, _, hidden, err := layout.TrySplitHidden(uv.Rect(0, 0, 6, 1))
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, hidden)
+	})
+
+	t.Run("node", func(t *testing.T) {
+		rects := Branch(
+			Horizontal(Prioritized{Constraint: Len(6), Priority: 1}, Fill(1)).WithSpacing(SpacingSpace(1)),
+			Leaf("status"),
+			Leaf("main"),
+		).Solve(uv.Rect(0, 0, 4, 1))
+
+		require.Equal(t, uv.Rect(0, 0, 0, 1), rects["status"])
+		require.Equal(t, uv.Rect(0, 0, 4, 1), rects["main"])
+	})
+}
@@ -10,3 +10,16 @@ type (
 func (SpacingSpace) isSpacing() {}
 
 func (SpacingOverlap) isSpacing() {}
+
+func spacingSize(spacing Spacing) int {
+	switch s := spacing.(type) {
+	case SpacingSpace:
+		return int(s)
+
+	case SpacingOverlap:
+		return -int(s)
+
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,236 @@
+package uvcasso
+
+import (
+	"fmt"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/metafates/uvcasso/casso"
+)
+
+// _relationEq is the default strength of relations.
+// Relations win over [Len] but give way to [Min] and [Max].
+const _relationEq casso.Strength = casso.Strong * 50.0
+
+// Segment refers to a segment of a named layout in a [System].
+type Segment struct {
+	Layout string
+	Index  int
+}
+
+// Relation relates the sizes of two segments:
+//
+//	size(A) op size(B) * Num / Den
+type Relation struct {
+	A, B Segment
+
+	Operator casso.RelationOperator
+
+	// Num and Den scale the size of B. Num must be at least 1,
+	// Den less than 1 is treated as 1.
+	Num, Den int
+
+	// Strength of the relation. When zero, relations win over [Len]
+	// but give way to [Min] and [Max].
+	Strength casso.Strength
+}
+
+// RelationEqual relates two segments to be of the same size.
+func RelationEqual(a, b Segment) Relation {
+	return Relation{
+		A:        a,
+		B:        b,
+		Operator: casso.RelationOperatorEqual,
+		Num:      1,
+		Den:      1,
+	}
+}
+
+// RelationGreaterOrEqual relates a segment to be at least as large as the other one.
+func RelationGreaterOrEqual(a, b Segment) Relation {
+	return Relation{
+		A:        a,
+		B:        b,
+		Operator: casso.RelationOperatorGreaterThanEqual,
+		Num:      1,
+		Den:      1,
+	}
+}
+
+// RelationRatio relates the size of a segment to be num/den of the other one.
+func RelationRatio(a, b Segment, num, den int) Relation {
+	return Relation{
+		A:        a,
+		B:        b,
+		Operator: casso.RelationOperatorEqual,
+		Num:      num,
+		Den:      den,
+	}
+}
+
+func (r Relation) WithStrength(strength casso.Strength) Relation {
+	r.Strength = strength
+	return r
+}
+
+func (r Relation) String() string {
+	return fmt.Sprintf("%s[%d] %s %s[%d] * %d / %d", r.A.Layout, r.A.Index, r.Operator, r.B.Layout, r.B.Index, r.Num, r.Den)
+}
+
+func (r Relation) valid() bool {
+	switch r.Operator {
+	case casso.RelationOperatorLessThanEqual, casso.RelationOperatorEqual, casso.RelationOperatorGreaterThanEqual:
+		return r.Num >= 1
+	default:
+		return false
+	}
+}
+
+// SystemLayout is a named layout of a [System] splitting the given area.
+type SystemLayout struct {
+	Name   string
+	Layout Layout
+	Area   uv.Rectangle
+}
+
+// System solves several layouts together, so that segments
+// of different layouts can be related to each other.
+type System struct {
+	Layouts   []SystemLayout
+	Relations []Relation
+}
+
+func NewSystem() System {
+	return System{}
+}
+
+// WithLayout adds a named layout splitting the given area.
+func (s System) WithLayout(name string, layout Layout, area uv.Rectangle) System {
+	s.Layouts = append(s.Layouts, SystemLayout{
+		Name:   name,
+		Layout: layout,
+		Area:   area,
+	})
+
+	return s
+}
+
+func (s System) WithRelations(relations ...Relation) System {
+	s.Relations = append(s.Relations, relations...)
+	return s
+}
+
+// TrySplit solves all layouts together and returns their segments by layout name.
+//
+// Returns [ErrDuplicateLayoutName] if layout names are not unique,
+// [ErrUnknownSegment] if a relation refers to a missing segment,
+// [ErrInvalidRelation] if a relation is not valid,
+// and otherwise the same errors as [Layout.TrySplit].
+func (s System) TrySplit() (map[string]Splitted, error) {
+	solver := casso.NewSolver()
+
+	segments := make(map[string][]_Element, len(s.Layouts))
+	areas := make(map[string]uv.Rectangle, len(s.Layouts))
+	layouts := make(map[string]Layout, len(s.Layouts))
+
+	for _, sl := range s.Layouts {
+		if _, ok := segments[sl.Name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateLayoutName, sl.Name)
+		}
+
+		l := sl.Layout.measure(sl.Area)
+		innerArea := l.Padding.Apply(sl.Area)
+
+		areaStart, areaEnd := innerArea.Min.Y, innerArea.Max.Y
+		if l.Direction == DirectionHorizontal {
+			areaStart, areaEnd = innerArea.Min.X, innerArea.Max.X
+		}
+
+		variables := newVariables(solver, sl.Name+".", len(l.Constraints))
+
+		area := _Element{
+			Start: variables[0],
+			End:   variables[len(variables)-1],
+		}
+
+		err := configureArea(
+			solver,
+			area,
+			float64(areaStart)*_floatPrecisionMultiplier,
+			float64(areaEnd)*_floatPrecisionMultiplier,
+		)
+		if err != nil {
+			return nil, wrapSolverError(fmt.Errorf("configure %s area: %w", sl.Name, err))
+		}
+
+		if err := configureLayout(solver, l, variables); err != nil {
+			return nil, wrapSolverError(fmt.Errorf("configure %s: %w", sl.Name, err))
+		}
+
+		segments[sl.Name] = newElements(variables[1:])
+		areas[sl.Name] = innerArea
+		layouts[sl.Name] = l
+	}
+
+	lookup := func(segment Segment) (_Element, error) {
+		elements := segments[segment.Layout]
+
+		if segment.Index < 0 || segment.Index >= len(elements) {
+			return _Element{}, fmt.Errorf("%w: %s[%d]", ErrUnknownSegment, segment.Layout, segment.Index)
+		}
+
+		return elements[segment.Index], nil
+	}
+
+	for _, r := range s.Relations {
+		if !r.valid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRelation, r)
+		}
+
+		a, err := lookup(r.A)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := lookup(r.B)
+		if err != nil {
+			return nil, err
+		}
+
+		size := b.size().MulConstant(float64(r.Num)).DivConstant(float64(max(1, r.Den)))
+
+		relation := casso.WeightedRelation{Operator: r.Operator, Strength: relationStrength(r.Strength)}
+
+		if err := solver.AddConstraint(relation.ExpressionLHS(a.size()).ExpressionRHS(size)); err != nil {
+			return nil, wrapSolverError(fmt.Errorf("add relation %s: %w", r, err))
+		}
+	}
+
+	changes := make([]float64, solver.VariableCount())
+	for _, c := range solver.FetchChanges() {
+		changes[c.Variable] = c.Constant
+	}
+
+	result := make(map[string]Splitted, len(s.Layouts))
+
+	for _, sl := range s.Layouts {
+		rects := changesToRects(changes, segments[sl.Name], areas[sl.Name], sl.Layout.Direction)
+
+		layouts[sl.Name].alignSegments(rects)
+
+		result[sl.Name] = rects
+	}
+
+	return result, nil
+}
+
+// Split solves all layouts together.
+//
+// It panics on error, see [System.TrySplit].
+func (s System) Split() map[string]Splitted {
+	result, err := s.TrySplit()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
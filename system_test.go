@@ -0,0 +1,82 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/metafates/uvcasso/casso"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem(t *testing.T) {
+	top := uv.Rect(0, 0, 40, 5)
+	bottom := uv.Rect(0, 5, 40, 5)
+
+	t.Run("aligned columns", func(t *testing.T) {
+		result := NewSystem().
+			WithLayout("top", Horizontal(Len(12), Fill(1)), top).
+			WithLayout("bottom", Horizontal(Fill(1), Fill(3)), bottom).
+			WithRelations(RelationEqual(Segment{"bottom", 0}, Segment{"top", 0})).
+			Split()
+
+		require.Equal(t, map[string]Splitted{
+			"top":    {uv.Rect(0, 0, 12, 5), uv.Rect(12, 0, 28, 5)},
+			"bottom": {uv.Rect(0, 5, 12, 5), uv.Rect(12, 5, 28, 5)},
+		}, result)
+	})
+
+	t.Run("relation wins over length", func(t *testing.T) {
+		result := NewSystem().
+			WithLayout("top", Horizontal(Len(12), Fill(1)), top).
+			WithLayout("bottom", Horizontal(Len(20), Fill(1)), bottom).
+			WithRelations(RelationRatio(Segment{"bottom", 0}, Segment{"top", 0}, 1, 2)).
+			Split()
+
+		require.Equal(t, 12, result["top"][0].Dx())
+		require.Equal(t, 6, result["bottom"][0].Dx())
+	})
+
+	t.Run("greater or equal", func(t *testing.T) {
+		system := NewSystem().
+			WithLayout("top", Horizontal(Min(12), Fill(1)), top).
+			WithLayout("bottom", Horizontal(Len(8), Fill(1)), bottom)
+
+		result := system.WithRelations(RelationGreaterOrEqual(Segment{"bottom", 0}, Segment{"top", 0})).Split()
+		require.Equal(t, 12, result["top"][0].Dx())
+		require.Equal(t, 12, result["bottom"][0].Dx())
+
+		result = system.WithRelations(RelationGreaterOrEqual(Segment{"top", 0}, Segment{"bottom", 0})).Split()
+		require.Equal(t, 12, result["top"][0].Dx())
+		require.Equal(t, 8, result["bottom"][0].Dx())
+	})
+
+	t.Run("custom strength", func(t *testing.T) {
+		result := NewSystem().
+			WithLayout("top", Horizontal(Len(12), Fill(1)), top).
+			WithLayout("bottom", Horizontal(Len(20), Fill(1)), bottom).
+			WithRelations(RelationEqual(Segment{"bottom", 0}, Segment{"top", 0}).WithStrength(casso.Weak)).
+			Split()
+
+		require.Equal(t, 20, result["bottom"][0].Dx())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewSystem().
+			WithLayout("top", Horizontal(Len(12), Fill(1)), top).
+			WithRelations(RelationEqual(Segment{"top", 0}, Segment{"top", 2})).
+			TrySplit()
+		require.ErrorIs(t, err, ErrUnknownSegment)
+
+		_, err = NewSystem().
+			WithLayout("top", Horizontal(Fill(1)), top).
+			WithLayout("top", Horizontal(Fill(1)), bottom).
+			TrySplit()
+		require.ErrorIs(t, err, ErrDuplicateLayoutName)
+
+		_, err = NewSystem().
+			WithLayout("top", Horizontal(Fill(1)), top).
+			WithRelations(Relation{A: Segment{"top", 0}, B: Segment{"top", 0}}).
+			TrySplit()
+		require.ErrorIs(t, err, ErrInvalidRelation)
+	})
+}
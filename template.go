@@ -0,0 +1,277 @@
+package uvcasso
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// Template is a grid of named areas, see [ParseTemplate].
+type Template struct {
+	// Areas maps the area names to the cells they cover.
+	Areas map[string]Placement
+
+	Rows, Columns int
+}
+
+type _TemplateCell struct {
+	name         string
+	line, column int
+}
+
+// ParseTemplate parses a template of named grid areas in the style of
+// CSS grid-template-areas:
+//
+//	"header header"
+//	"nav    main"
+//	"footer footer"
+//
+// Rows are double quoted and separated by whitespace or "/".
+// Cells of a row are separated by whitespace, a "." (or several) marks
+// an empty cell. Every area must form a rectangle.
+//
+// When names are given, areas with other names are rejected.
+//
+// Errors are of type [*TemplateError].
+func ParseTemplate(template string, names ...string) (Template, error) {
+	rows, err := tokenizeTemplate(template)
+	if err != nil {
+		return Template{}, err
+	}
+
+	if len(rows) == 0 {
+		return Template{}, &TemplateError{Line: 1, Column: 1, Message: "empty template"}
+	}
+
+	columns := len(rows[0])
+
+	for _, row := range rows {
+		if len(row) != columns {
+			return Template{}, &TemplateError{
+				Line:    row[0].line,
+				Column:  row[0].column,
+				Message: fmt.Sprintf("row has %d cells, expected %d", len(row), columns),
+			}
+		}
+	}
+
+	t := Template{
+		Areas:   make(map[string]Placement),
+		Rows:    len(rows),
+		Columns: columns,
+	}
+
+	// Names in the order of their first cell, so that errors are reported deterministically.
+	var order []string
+
+	// Bounding boxes of the areas.
+	for r, row := range rows {
+		for c, cell := range row {
+			if cell.name == "" {
+				continue
+			}
+
+			if len(names) > 0 && !slices.Contains(names, cell.name) {
+				return Template{}, &TemplateError{
+					Line:    cell.line,
+					Column:  cell.column,
+					Message: fmt.Sprintf("unknown area %q", cell.name),
+				}
+			}
+
+			p, ok := t.Areas[cell.name]
+			if !ok {
+				t.Areas[cell.name] = Placement{Row: r, Column: c, RowSpan: 1, ColumnSpan: 1}
+				order = append(order, cell.name)
+
+				continue
+			}
+
+			firstColumn := min(p.Column, c)
+
+			p.ColumnSpan = max(p.lastColumn(), c) - firstColumn + 1
+			p.Column = firstColumn
+			p.RowSpan = r - p.Row + 1
+
+			t.Areas[cell.name] = p
+		}
+	}
+
+	// Every cell inside the bounding box must belong to the area.
+	for r, row := range rows {
+		for c, cell := range row {
+			for _, name := range order {
+				p := t.Areas[name]
+				inside := r >= p.Row && r <= p.lastRow() && c >= p.Column && c <= p.lastColumn()
+
+				if inside && cell.name != name {
+					return Template{}, &TemplateError{
+						Line:    cell.line,
+						Column:  cell.column,
+						Message: fmt.Sprintf("area %q is not rectangular", name),
+					}
+				}
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// MustParseTemplate is like [ParseTemplate] but panics on error.
+func MustParseTemplate(template string, names ...string) Template {
+	t, err := ParseTemplate(template, names...)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// Place returns the rectangles of the template areas in the given cells.
+//
+// Returns [ErrTemplateMismatch] if the cells do not match the template size.
+func (t Template) Place(cells Cells) (map[string]uv.Rectangle, error) {
+	var columns int
+	if len(cells) > 0 {
+		columns = len(cells[0])
+	}
+
+	if len(cells) != t.Rows || columns != t.Columns {
+		return nil, fmt.Errorf(
+			"template has %dx%d cells, grid has %dx%d: %w",
+			t.Rows, t.Columns, len(cells), columns, ErrTemplateMismatch,
+		)
+	}
+
+	areas := make(map[string]uv.Rectangle, len(t.Areas))
+
+	for name, p := range t.Areas {
+		areas[name] = cells.Span(p)
+	}
+
+	return areas, nil
+}
+
+func tokenizeTemplate(template string) ([][]_TemplateCell, error) {
+	var (
+		rows [][]_TemplateCell
+		row  []_TemplateCell
+
+		inRow        bool
+		line, column = 1, 0
+
+		rowLine, rowColumn int
+		cell               *_TemplateCell
+	)
+
+	endCell := func() {
+		if cell != nil {
+			row = append(row, *cell)
+			cell = nil
+		}
+	}
+
+	for _, r := range template {
+		column++
+
+		if r == '\n' {
+			if inRow {
+				return nil, &TemplateError{Line: rowLine, Column: rowColumn, Message: "unterminated row"}
+			}
+
+			line++
+			column = 0
+
+			continue
+		}
+
+		switch {
+		case !inRow && r == '"':
+			inRow = true
+			rowLine, rowColumn = line, column
+			row = nil
+
+		case !inRow && (r == '/' || unicode.IsSpace(r)):
+
+		case !inRow:
+			return nil, &TemplateError{
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("unexpected %q, expected a quoted row", r),
+			}
+
+		case r == '"':
+			endCell()
+
+			if len(row) == 0 {
+				return nil, &TemplateError{Line: rowLine, Column: rowColumn, Message: "empty row"}
+			}
+
+			rows = append(rows, row)
+			inRow = false
+
+		case unicode.IsSpace(r):
+			endCell()
+
+		case r == '.':
+			if cell != nil && cell.name != "" {
+				endCell()
+			}
+
+			if cell == nil {
+				cell = &_TemplateCell{line: line, column: column}
+			}
+
+		case r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			if cell != nil && cell.name == "" {
+				endCell()
+			}
+
+			if cell == nil {
+				cell = &_TemplateCell{line: line, column: column}
+			}
+
+			cell.name += string(r)
+
+		default:
+			return nil, &TemplateError{
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("unexpected %q in area name", r),
+			}
+		}
+	}
+
+	if inRow {
+		return nil, &TemplateError{Line: rowLine, Column: rowColumn, Message: "unterminated row"}
+	}
+
+	return rows, nil
+}
+
+// TryAreas splits the given area and returns the rectangles of the template areas.
+//
+// See [Grid.TrySplit] and [Template.Place] for the returned errors.
+func (g Grid) TryAreas(area uv.Rectangle, template Template) (map[string]uv.Rectangle, error) {
+	cells, err := g.TrySplit(area)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.Place(cells)
+}
+
+// Areas splits the given area and returns the rectangles of the template areas.
+//
+// It panics on error, see [Grid.TryAreas].
+func (g Grid) Areas(area uv.Rectangle, template Template) map[string]uv.Rectangle {
+	areas, err := g.TryAreas(area, template)
+	if err != nil {
+		panic(err)
+	}
+
+	return areas
+}
@@ -0,0 +1,126 @@
+package uvcasso
+
+import (
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplate(t *testing.T) {
+	t.Run("areas", func(t *testing.T) {
+		template, err := ParseTemplate(`"header header" / "nav main" / "footer footer"`)
+		require.NoError(t, err)
+
+		require.Equal(t, Template{
+			Areas: map[string]Placement{
+				"header": {Row: 0, Column: 0, RowSpan: 1, ColumnSpan: 2},
+				"nav":    {Row: 1, Column: 0, RowSpan: 1, ColumnSpan: 1},
+				"main":   {Row: 1, Column: 1, RowSpan: 1, ColumnSpan: 1},
+				"footer": {Row: 2, Column: 0, RowSpan: 1, ColumnSpan: 2},
+			},
+			Rows:    3,
+			Columns: 2,
+		}, template)
+	})
+
+	t.Run("multiline with empty cells", func(t *testing.T) {
+		template, err := ParseTemplate(`
+			"...  side-bar"
+			"main side-bar"
+			"main ."
+		`)
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]Placement{
+			"side-bar": {Row: 0, Column: 1, RowSpan: 2, ColumnSpan: 1},
+			"main":     {Row: 1, Column: 0, RowSpan: 2, ColumnSpan: 1},
+		}, template.Areas)
+	})
+
+	testCases := []struct {
+		name     string
+		template string
+		names    []string
+		want     TemplateError
+	}{
+		{
+			name:     "empty",
+			template: "  ",
+			want:     TemplateError{Line: 1, Column: 1, Message: "empty template"},
+		},
+		{
+			name:     "empty row",
+			template: `"a" ""`,
+			want:     TemplateError{Line: 1, Column: 5, Message: "empty row"},
+		},
+		{
+			name:     "unquoted",
+			template: `"a" / b`,
+			want:     TemplateError{Line: 1, Column: 7, Message: `unexpected 'b', expected a quoted row`},
+		},
+		{
+			name:     "unterminated",
+			template: "\"a b\"\n\"c d\n",
+			want:     TemplateError{Line: 2, Column: 1, Message: "unterminated row"},
+		},
+		{
+			name:     "invalid name",
+			template: `"a b!"`,
+			want:     TemplateError{Line: 1, Column: 5, Message: `unexpected '!' in area name`},
+		},
+		{
+			name:     "row size",
+			template: "\"a b\"\n  \"c\"",
+			want:     TemplateError{Line: 2, Column: 4, Message: "row has 1 cells, expected 2"},
+		},
+		{
+			name:     "not rectangular",
+			template: "\"a a\"\n\"a b\"",
+			want:     TemplateError{Line: 2, Column: 4, Message: `area "a" is not rectangular`},
+		},
+		{
+			name:     "disjoint",
+			template: `"a b a"`,
+			want:     TemplateError{Line: 1, Column: 4, Message: `area "a" is not rectangular`},
+		},
+		{
+			name:     "unknown",
+			template: "\"header header\"\n\"nav  mian\"",
+			names:    []string{"header", "nav", "main"},
+			want:     TemplateError{Line: 2, Column: 7, Message: `unknown area "mian"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTemplate(tc.template, tc.names...)
+
+			var target *TemplateError
+
+			require.ErrorAs(t, err, &target)
+			require.Equal(t, tc.want, *target)
+		})
+	}
+}
+
+func TestGridAreas(t *testing.T) {
+	template := MustParseTemplate(`"header header" / "nav main" / "footer footer"`)
+
+	grid := NewGrid(
+		[]Constraint{Len(1), Fill(1), Len(1)},
+		[]Constraint{Len(10), Fill(1)},
+	).WithSpacing(SpacingSpace(1))
+
+	areas := grid.Areas(uv.Rect(0, 0, 40, 12), template)
+
+	require.Equal(t, map[string]uv.Rectangle{
+		"header": uv.Rect(0, 0, 40, 1),
+		"nav":    uv.Rect(0, 2, 10, 8),
+		"main":   uv.Rect(11, 2, 29, 8),
+		"footer": uv.Rect(0, 11, 40, 1),
+	}, areas)
+
+	_, err := grid.WithColumns(Fill(1)).TryAreas(uv.Rect(0, 0, 40, 12), template)
+	require.ErrorIs(t, err, ErrTemplateMismatch)
+}